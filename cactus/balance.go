@@ -0,0 +1,126 @@
+package cactus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go-cactus/model"
+)
+
+// ErrAddressNotFound 钱包中没有该币种的这个地址
+var ErrAddressNotFound = errors.New("address not found")
+
+// ErrNoWallets 没有指定钱包，客户端Profile中也没有配置钱包
+var ErrNoWallets = errors.New("no wallet codes given or configured in the client profile")
+
+// GetWalletBalance 查询钱包各币种的总额、可用和冻结余额
+func (c *ClientImpl) GetWalletBalance(ctx context.Context, req *model.GetWalletBalanceReq) (*model.WalletBalance, error) {
+	resp, err := c.GetWallet(ctx, &model.GetWalletReq{
+		BID:        req.BID,
+		WalletCode: req.WalletCode,
+		CoinNames:  req.CoinNames,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &model.WalletBalance{
+		WalletCode: resp.Data.WalletCode,
+		WalletType: resp.Data.WalletType,
+		Balances:   make([]model.CoinBalance, 0, len(resp.Data.CoinList)),
+	}
+	for _, coin := range resp.Data.CoinList {
+		balance.Balances = append(balance.Balances, model.CoinBalance{
+			CoinName:  coin.CoinName,
+			Total:     coin.TotalAmount,
+			Available: coin.AvailableAmount,
+			Frozen:    coin.FreezeAmount,
+		})
+	}
+	return balance, nil
+}
+
+// GetAddressBalance 查询单个地址某币种的余额，通过地址列表按关键字精确匹配
+func (c *ClientImpl) GetAddressBalance(ctx context.Context, req *model.GetAddressBalanceReq) (*model.AddressBalance, error) {
+	info, err := c.findAddress(ctx, req.BID, req.WalletCode, req.CoinName, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrAddressNotFound, req.CoinName, req.Address)
+	}
+	return &model.AddressBalance{
+		WalletCode: info.WalletCode,
+		Address:    info.Address,
+		CoinBalance: model.CoinBalance{
+			CoinName:  info.CoinName,
+			Total:     info.TotalAmount,
+			Available: info.AvailableAmount,
+			Frozen:    info.FreezeAmount,
+		},
+	}, nil
+}
+
+// errAddressFound 找到地址时用于提前结束翻页
+var errAddressFound = errors.New("address found")
+
+// findAddress 按关键字翻页查找与address完全一致的地址，关键字是模糊匹配，完全一致的地址可能不在第一页。
+// 找不到时返回nil
+func (c *ClientImpl) findAddress(ctx context.Context, bid, walletCode, coinName, address string) (*model.AddressInfo, error) {
+	var found *model.AddressInfo
+	err := eachPage(func(offset, limit int) (*model.GetAddressesResp, error) {
+		return c.GetAddressList(ctx, &model.GetAddressesReq{
+			BID:        bid,
+			WalletCode: walletCode,
+			CoinName:   coinName,
+			KeyWord:    &address,
+			Offset:     &offset,
+			Limit:      &limit,
+		})
+	}, func(info model.AddressInfo) error {
+		if info.Address != address {
+			return nil
+		}
+		found = &info
+		return errAddressFound
+	})
+	if err != nil && !errors.Is(err, errAddressFound) {
+		return nil, err
+	}
+	return found, nil
+}
+
+// AggregateBalances 汇总多个钱包的余额，walletCodes为空时使用客户端Profile中的钱包，两者都为空时返回ErrNoWallets
+func AggregateBalances(ctx context.Context, client Client, walletCodes ...string) (*model.BalanceReport, error) {
+	if len(walletCodes) == 0 {
+		walletCodes = client.WalletCodes()
+	}
+	if len(walletCodes) == 0 {
+		return nil, ErrNoWallets
+	}
+
+	report := &model.BalanceReport{}
+	totals := make(map[string]model.CoinBalance)
+	for _, code := range walletCodes {
+		balance, err := client.GetWalletBalance(ctx, &model.GetWalletBalanceReq{WalletCode: code})
+		if err != nil {
+			return nil, err
+		}
+		report.Wallets = append(report.Wallets, *balance)
+		for _, b := range balance.Balances {
+			total, ok := totals[b.CoinName]
+			if !ok {
+				total = model.CoinBalance{CoinName: b.CoinName}
+			}
+			totals[b.CoinName] = total.Add(b)
+		}
+	}
+
+	for _, total := range totals {
+		report.Totals = append(report.Totals, total)
+	}
+	sort.Slice(report.Totals, func(i, j int) bool { return report.Totals[i].CoinName < report.Totals[j].CoinName })
+	return report, nil
+}
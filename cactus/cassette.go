@@ -0,0 +1,13 @@
+package cactus
+
+import "go-cactus/httpclient"
+
+// NewCassette 创建用于录制的磁带，按签名时相同的规则（formatURIParameters）规范化URI
+func NewCassette(path string, opts ...httpclient.CassetteOption) *httpclient.Cassette {
+	return httpclient.NewCassette(path, append([]httpclient.CassetteOption{httpclient.WithURIFormatter(formatURIParameters)}, opts...)...)
+}
+
+// LoadCassette 读取已录制的磁带用于回放，URI规范化规则与NewCassette一致
+func LoadCassette(path string, opts ...httpclient.CassetteOption) (*httpclient.Cassette, error) {
+	return httpclient.LoadCassette(path, append([]httpclient.CassetteOption{httpclient.WithURIFormatter(formatURIParameters)}, opts...)...)
+}
@@ -6,21 +6,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"time"
 
-	"crypto/ecdsa"
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/asn1"
-	"encoding/base64"
 	"encoding/json"
-	"math/big"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"go-cactus/httpclient"
+	"go-cactus/metrics"
 	"go-cactus/model"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 // Client 定义与Cactus API交互的接口
@@ -29,12 +30,54 @@ type Client interface {
 	CheckAddress(ctx context.Context, req *model.CheckAddressReq) (*model.CheckAddressResp, error)
 	// CreateOrder 创建提币订单
 	CreateOrder(ctx context.Context, req *model.CreateOrderReq) (*model.CreateOrderResp, error)
+	// TransferBetweenWallets 在自有钱包之间转账，未指定入账地址时自动从入账钱包选取
+	TransferBetweenWallets(ctx context.Context, req *model.TransferReq) (*model.CreateOrderResp, error)
+	// GetOrder 按订单号查询提币订单
+	GetOrder(ctx context.Context, req *model.GetOrderReq) (*model.GetOrderResp, error)
+	// ListOrders 按状态、币种、时间范围查询提币订单
+	ListOrders(ctx context.Context, req *model.ListOrdersReq) (*model.ListOrdersResp, error)
+	// CancelOrder 在审批通过或广播之前取消提币订单
+	CancelOrder(ctx context.Context, req *model.CancelOrderReq) (*model.CancelOrderResp, error)
+	// EstimateFee 提币前预估各档位手续费
+	EstimateFee(ctx context.Context, req *model.EstimateFeeReq) (*model.EstimateFeeResp, error)
 	// TxDetail 查询钱包记录明细
 	TxDetail(ctx context.Context, req *model.TxDetailReq) (*model.TxDetailResp, error)
 	// TxSummary 查询钱包交易记录概要
 	TxSummary(ctx context.Context, req *model.TxSummaryReq) (*model.TxSummaryResp, error)
 	// GetAddressList 获取该钱包所有地址
 	GetAddressList(ctx context.Context, req *model.GetAddressesReq) (*model.GetAddressesResp, error)
+	// CreateAddresses 在钱包下批量生成新地址
+	CreateAddresses(ctx context.Context, req *model.CreateAddressesReq) (*model.CreateAddressesResp, error)
+	// UpdateAddressDescription 修改地址描述
+	UpdateAddressDescription(ctx context.Context, req *model.UpdateAddressDescriptionReq) (*model.UpdateAddressDescriptionResp, error)
+	// ListCoins 查询币种元数据（链、合约地址、精度、memo、最小提币量、手续费币种）
+	ListCoins(ctx context.Context, req *model.ListCoinsReq) (*model.ListCoinsResp, error)
+	// GetCoinInfo 查询单个币种的元数据，带本地TTL缓存
+	GetCoinInfo(ctx context.Context, coinName string) (*model.CoinInfo, error)
+	// ListWallets 分页查询业务线下的钱包
+	ListWallets(ctx context.Context, req *model.ListWalletsReq) (*model.ListWalletsResp, error)
+	// GetWallet 查询单个钱包详情
+	GetWallet(ctx context.Context, req *model.GetWalletReq) (*model.GetWalletResp, error)
+	// GetWalletBalance 查询钱包各币种的总额、可用和冻结余额
+	GetWalletBalance(ctx context.Context, req *model.GetWalletBalanceReq) (*model.WalletBalance, error)
+	// GetAddressBalance 查询单个地址某币种的余额
+	GetAddressBalance(ctx context.Context, req *model.GetAddressBalanceReq) (*model.AddressBalance, error)
+
+	// ListWhitelist 查询提币白名单
+	ListWhitelist(ctx context.Context, req *model.ListWhitelistReq) (*model.ListWhitelistResp, error)
+	// AddWhitelistAddresses 添加提币白名单地址
+	AddWhitelistAddresses(ctx context.Context, req *model.AddWhitelistAddressesReq) (*model.AddWhitelistAddressesResp, error)
+	// RemoveWhitelistAddresses 移除提币白名单地址
+	RemoveWhitelistAddresses(ctx context.Context, req *model.RemoveWhitelistAddressesReq) (*model.RemoveWhitelistAddressesResp, error)
+
+	// Environment 返回客户端当前的环境
+	Environment() Environment
+
+	// WalletCodes 返回客户端Profile中配置了的钱包编号
+	WalletCodes() []string
+
+	// Do 调用库中尚未封装的Cactus接口，签名、请求头和错误处理与内置接口一致
+	Do(ctx context.Context, method, path string, query url.Values, body, out any) error
 
 	// GetPublicIP 获取当前的公共 IP 地址（在白名单内的IP才可以访问Cactus）
 	GetPublicIP(ctx context.Context) (string, error)
@@ -42,63 +85,121 @@ type Client interface {
 
 // ClientImpl 实现了Client接口
 type ClientImpl struct {
-	baseURL    string                 //第三方api所在URL
-	privateKey *ecdsa.PrivateKey      //私钥
-	client     *httpclient.HTTPClient //客户端
+	baseURL  string                 //第三方api所在URL
+	profile  Profile                //环境配置：api key、业务线和钱包
+	keys     *KeyRing               //签名凭证（主、备）
+	signer   Signer                 //WithPrivateKey指定的签名器，在NewClient中与Profile的AK ID组成凭证
+	client   *httpclient.HTTPClient //客户端
+	httpOpts []httpclient.Option    //底层HTTP客户端的额外配置
+	tracer   trace.Tracer           //链路追踪
+	metrics  *metrics.ClientMetrics //监控指标，为nil时不采集
+	logger   *log.Logger            //日志
+
+	clock          Clock     //时间来源
+	skew           clockSkew //与服务端的时钟偏差
+	skewCorrection bool      //是否用时钟偏差校正Date请求头
+
+	signatureFallback bool //主凭证签名被拒绝时是否回退到备用凭证
+
+	coins coinCache //币种元数据缓存
+
+	whitelistPrecheck bool //创建订单前是否检查收款地址在白名单内
+
+	productionFundMoves bool     //是否允许在生产环境动用资金
+	readOnlyPaths       []string //生产环境下允许通过Do调用的其他非GET接口
 }
 
-// NewClient 创建一个新的Cactus客户端
-func NewClient() Client {
-	return &ClientImpl{
-		baseURL:    model.URL_PRE,
-		privateKey: InitPrivateKey(),
-		client: httpclient.NewHTTPClient(
-			httpclient.WithTimeout(30*time.Second),
-			httpclient.WithMaxRetries(3),
-			httpclient.WithInsecureSkipVerify(true),
-		),
+// NewClient 创建一个新的Cactus客户端。未通过配置指定签名方式时从model.SIGN_PIRVATE_PATH加载私钥，
+// 加载失败时返回错误
+func NewClient(opts ...Option) (Client, error) {
+	c := &ClientImpl{
+		baseURL:        model.URL_PRE,
+		profile:        defaultProfile(),
+		tracer:         noop.NewTracerProvider().Tracer(tracerName),
+		logger:         log.Default(),
+		clock:          systemClock{},
+		skew:           clockSkew{threshold: defaultSkewThreshold},
+		skewCorrection: true,
+		coins:          coinCache{ttl: defaultCoinCacheTTL},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.keys == nil {
+		cred := c.profile.Credential
+		if c.signer != nil {
+			cred.Signer = c.signer
+		}
+		if cred.Signer == nil {
+			key, err := InitPrivateKey()
+			if err != nil {
+				return nil, err
+			}
+			cred.Signer = NewECDSASigner(key)
+		}
+		c.keys = NewKeyRing(cred, nil)
+	}
+	httpOpts := []httpclient.Option{
+		httpclient.WithTimeout(30 * time.Second),
+		httpclient.WithMaxRetries(3),
+		httpclient.WithInsecureSkipVerify(true),
 	}
+	c.client = httpclient.NewHTTPClient(append(httpOpts, c.httpOpts...)...)
+	return c, nil
 }
 
-// Sign 进行签名
+// Sign 使用主凭证进行签名，签名失败时返回空字符串
 func (c *ClientImpl) Sign(content string) string {
-	hashed := sha256.Sum256([]byte(content))
-	r, s, _ := ecdsa.Sign(rand.Reader, c.privateKey, hashed[:])
+	sign, _ := c.keys.Primary().Signer.Sign(content)
+	return sign
+}
 
-	// 将r和s转换为ASN.1 DER格式
-	type ecdsaSignature struct {
-		R, S *big.Int
+// buildRequest 构造请求，主凭证签名被拒绝且开启了回退时改用备用凭证重发一次
+func (c *ClientImpl) buildRequest(ctx context.Context, method, uri string, body []byte) ([]byte, error) {
+	primary := c.keys.Primary()
+	status, respBody, err := c.send(ctx, primary, method, uri, body)
+	if err != nil || !c.signatureFallback || !isSignatureError(status) {
+		return respBody, err
 	}
-	sigAsn1, _ := asn1.Marshal(ecdsaSignature{r, s})
-
-	return base64.StdEncoding.EncodeToString(sigAsn1)
+	secondary, ok := c.keys.Secondary()
+	if !ok {
+		return respBody, nil
+	}
+	c.logger.Printf("cactus[%s]: signature of ak_id %s rejected with status %d, retrying with secondary ak_id %s", c.profile.Name, primary.AKID, status, secondary.AKID)
+	_, respBody, err = c.send(ctx, secondary, method, uri, body)
+	return respBody, err
 }
 
-// buildRequest 构造请求
-func (c *ClientImpl) buildRequest(ctx context.Context, method, uri string, body []byte) ([]byte, error) {
+// send 使用指定凭证签名并发送请求，返回HTTP状态码和响应体
+func (c *ClientImpl) send(ctx context.Context, cred Credential, method, uri string, body []byte) (int, []byte, error) {
 	//0.生成唯一标识
-	date := time.Now().UTC().Format(model.TimeFormat)
+	date := c.now().UTC().Format(model.TimeFormat)
 	nonce := uuid.New().String()
 
 	//1.构造签名体并进行签名
-	signContent, err := buildContentToSign(method, uri, date, nonce, body)
+	signContent, err := buildContentToSign(method, uri, date, c.profile.APIKey, nonce, body)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
+	}
+	signStart := time.Now()
+	sign, err := cred.Signer.Sign(signContent)
+	c.metrics.ObserveSigning(time.Since(signStart))
+	if err != nil {
+		return 0, nil, fmt.Errorf("sign request with ak_id %s: %w", cred.AKID, err)
 	}
-	sign := c.Sign(signContent)
 
 	//2.构造Authorization
-	auth := buildAuthorization(sign)
+	auth := buildAuthorization(cred.AKID, sign)
 
 	//3.生成一个请求头
-	req, err := http.NewRequest(method, c.baseURL+uri, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+uri, bytes.NewBuffer(body))
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 	headers := req.Header
 
 	//4.把相应信息放入请求头
-	headers.Set("x-api-key", model.API_KEY)
+	headers.Set("x-api-key", c.profile.APIKey)
 	headers.Set("x-api-nonce", nonce)
 	headers.Set("Accept", "application/json")
 	headers.Set("Date", date)
@@ -109,116 +210,426 @@ func (c *ClientImpl) buildRequest(ctx context.Context, method, uri string, body
 	}
 
 	//5.发送请求
+	start := time.Now()
+	sent := c.clock.Now()
 	resp, err := c.client.Do(ctx, req)
 	if err != nil {
-		fmt.Println(err)
-		return nil, err
+		c.metrics.ObserveRequest(operationFromContext(ctx), 0, "", time.Since(start))
+		return 0, nil, fmt.Errorf("send %s %s: %w", method, uri, err)
 	}
+	defer resp.Body.Close()
+	c.observeServerDate(resp, sent, c.clock.Now())
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Println(err)
-		return nil, err
+		c.metrics.ObserveRequest(operationFromContext(ctx), resp.StatusCode, "", time.Since(start))
+		return resp.StatusCode, nil, fmt.Errorf("read response of %s %s: %w", method, uri, err)
 	}
-	return respBody, nil
+
+	code, hasCode := responseCode(respBody)
+	codeLabel := ""
+	if hasCode {
+		codeLabel = strconv.Itoa(code)
+	}
+	c.metrics.ObserveRequest(operationFromContext(ctx), resp.StatusCode, codeLabel, time.Since(start))
+	recordResponse(ctx, method, uri, code, hasCode)
+	return resp.StatusCode, respBody, nil
 }
 
-// CheckAddress 检验地址是否合法
-func (c *ClientImpl) CheckAddress(ctx context.Context, req *model.CheckAddressReq) (*model.CheckAddressResp, error) {
-	uri := "/custody/v1/api/addresses/type/check"
-	body, err := json.Marshal(*req)
-	if err != nil {
-		return nil, errors.New("json marshal fail")
+// envelopeHead Cactus响应中的返回码和错误信息
+type envelopeHead struct {
+	Code    *int   `json:"code"`
+	Message string `json:"message"`
+}
+
+// responseCode 从响应体中取出Cactus返回码
+func responseCode(respBody []byte) (int, bool) {
+	var head envelopeHead
+	if err := json.Unmarshal(respBody, &head); err != nil || head.Code == nil {
+		return 0, false
 	}
+	return *head.Code, true
+}
 
-	resp, err := c.buildRequest(ctx, http.MethodPost, uri, body)
-	if err != nil {
-		return nil, err
+// APIError Cactus返回了非0的code
+type APIError struct {
+	Environment Environment // 客户端所在环境
+	Operation   string      // 接口名，如CreateOrder
+	Code        int         // Cactus返回码
+	Message     string      // Cactus错误信息
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("cactus[%s] %s failed: code=%d message=%s", e.Environment, e.Operation, e.Code, e.Message)
+}
+
+// pathf 拼接接口路径，路径参数会被转义
+func pathf(format string, params ...string) string {
+	args := make([]any, len(params))
+	for i, p := range params {
+		args[i] = url.PathEscape(p)
 	}
+	return fmt.Sprintf(format, args...)
+}
 
-	var result model.CheckAddressResp
-	err = json.Unmarshal(resp, &result)
+// do 签名并发送请求，body为nil时不带请求体；解析响应并检查返回码，非0时返回*APIError
+func do[Req, Resp any](ctx context.Context, c *ClientImpl, method, path string, query url.Values, body *Req) (*Resp, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("json marshal fail: %w", err)
+		}
+	}
+	respBody, err := c.buildRequest(ctx, method, withQuery(path, query), payload)
 	if err != nil {
-		return nil, errors.New("json unmarshal fail")
+		return nil, err
 	}
 
+	var head envelopeHead
+	if err := json.Unmarshal(respBody, &head); err != nil {
+		return nil, fmt.Errorf("json unmarshal fail: %w", err)
+	}
+	if head.Code != nil && *head.Code != 0 {
+		return nil, &APIError{Environment: c.profile.Name, Operation: operationFromContext(ctx), Code: *head.Code, Message: head.Message}
+	}
+	var result Resp
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("json unmarshal fail: %w", err)
+	}
 	return &result, nil
 }
 
+// CheckAddress 检验地址是否合法
+func (c *ClientImpl) CheckAddress(ctx context.Context, req *model.CheckAddressReq) (_ *model.CheckAddressResp, err error) {
+	ctx, span := c.startSpan(ctx, "CheckAddress", attrCoinName.String(req.CoinName))
+	defer func() { endSpan(span, err) }()
+
+	return do[model.CheckAddressReq, model.CheckAddressResp](ctx, c, http.MethodPost,
+		"/custody/v1/api/addresses/type/check", nil, req)
+}
+
 // CreateOrder 创建提币订单
-func (c *ClientImpl) CreateOrder(ctx context.Context, req *model.CreateOrderReq) (*model.CreateOrderResp, error) {
-	uri := fmt.Sprintf("/custody/v1/api/projects/%s/order/create", model.Bid)
-	body, err := json.Marshal(*req)
-	if err != nil {
-		return nil, errors.New("json marshal fail")
-	}
-	resp, err := c.buildRequest(ctx, http.MethodPost, uri, body)
-	if err != nil {
+func (c *ClientImpl) CreateOrder(ctx context.Context, req *model.CreateOrderReq) (_ *model.CreateOrderResp, err error) {
+	ctx, span := c.startSpan(ctx, "CreateOrder",
+		attrCoinName.String(req.CoinName),
+		attrOrderNo.String(req.OrderNo),
+		attrWalletCode.String(req.FromWalletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	if err := c.guardFundMoving("CreateOrder"); err != nil {
 		return nil, err
 	}
-	var result model.CreateOrderResp
-	err = json.Unmarshal(resp, &result)
-	if err != nil {
-		return nil, errors.New("json unmarshal fail")
+	if c.whitelistPrecheck {
+		if err := c.checkWhitelist(ctx, req); err != nil {
+			return nil, err
+		}
 	}
-	return &result, nil
+	return do[model.CreateOrderReq, model.CreateOrderResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/order/create", c.projectID(req.BID)), nil, req)
 }
 
-// TxDetail 查询钱包记录明细
-func (c *ClientImpl) TxDetail(ctx context.Context, req *model.TxDetailReq) (*model.TxDetailResp, error) {
-	uri := fmt.Sprintf("/custody/v1/api/projects/%s/wallets/%s/tx-details?tx_types=WITHDRAW,DEPOSIT&id=%d", req.BID, req.WalletCode, req.ID) //按需调整参数
-	resp, err := c.buildRequest(ctx, http.MethodGet, uri, nil)
+// GetOrder 按订单号查询提币订单
+func (c *ClientImpl) GetOrder(ctx context.Context, req *model.GetOrderReq) (_ *model.GetOrderResp, err error) {
+	ctx, span := c.startSpan(ctx, "GetOrder", attrOrderNo.String(req.OrderNo))
+	defer func() { endSpan(span, err) }()
+
+	return do[model.GetOrderReq, model.GetOrderResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/orders/%s", c.projectID(req.BID), req.OrderNo), nil, nil)
+}
+
+// ListOrders 按状态、币种、时间范围查询提币订单
+func (c *ClientImpl) ListOrders(ctx context.Context, req *model.ListOrdersReq) (_ *model.ListOrdersResp, err error) {
+	ctx, span := c.startSpan(ctx, "ListOrders",
+		attrCoinName.String(req.CoinName),
+		attrWalletCode.String(req.WalletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	statuses := make([]string, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		statuses = append(statuses, string(status))
+	}
+	q := url.Values{}
+	setStrings(q, "status", statuses)
+	setString(q, "coin_name", req.CoinName)
+	setString(q, "wallet_code", req.WalletCode)
+	setTimestamp(q, "start_time", req.StartTime)
+	setTimestamp(q, "end_time", req.EndTime)
+	setInt(q, "offset", req.Offset)
+	setInt(q, "limit", req.Limit)
+	return do[model.ListOrdersReq, model.ListOrdersResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/orders", c.projectID(req.BID)), q, nil)
+}
+
+// CancelOrder 在审批通过或广播之前取消提币订单
+func (c *ClientImpl) CancelOrder(ctx context.Context, req *model.CancelOrderReq) (_ *model.CancelOrderResp, err error) {
+	ctx, span := c.startSpan(ctx, "CancelOrder", attrOrderNo.String(req.OrderNo))
+	defer func() { endSpan(span, err) }()
+
+	return do[model.CancelOrderReq, model.CancelOrderResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/orders/%s/cancel", c.projectID(req.BID), req.OrderNo), nil, req)
+}
+
+// EstimateFee 提币前预估各档位手续费，并计算每个档位的总花费
+func (c *ClientImpl) EstimateFee(ctx context.Context, req *model.EstimateFeeReq) (_ *model.EstimateFeeResp, err error) {
+	ctx, span := c.startSpan(ctx, "EstimateFee",
+		attrCoinName.String(req.CoinName),
+		attrWalletCode.String(req.FromWalletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	result, err := do[model.EstimateFeeReq, model.EstimateFeeResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/order/estimate-fee", c.projectID(req.BID)), nil, req)
 	if err != nil {
 		return nil, err
 	}
-	var result model.TxDetailResp
-	err = json.Unmarshal(resp, &result)
-	if err != nil {
-		return nil, errors.New("json unmarshal fail")
+	c.fillFeeTotals(ctx, req, result.Data)
+	return result, nil
+}
+
+// TxDetail 查询钱包记录明细
+func (c *ClientImpl) TxDetail(ctx context.Context, req *model.TxDetailReq) (_ *model.TxDetailResp, err error) {
+	walletCode := c.walletCode(req.WalletCode)
+	ctx, span := c.startSpan(ctx, "TxDetail",
+		attrCoinName.String(req.CoinName),
+		attrOrderNo.String(req.OrderNo),
+		attrWalletCode.String(walletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	txTypes := req.TxTypes
+	if len(txTypes) == 0 {
+		txTypes = defaultTxTypes
 	}
-	return &result, nil
+	q := url.Values{}
+	setString(q, "coin_name", req.CoinName)
+	setStrings(q, "tx_types", txTypes)
+	setStrings(q, "addresses", req.Addresses)
+	if req.ID != 0 {
+		setInt64(q, "id", &req.ID)
+	}
+	if req.TxID != nil {
+		setString(q, "tx_id", *req.TxID)
+	}
+	setString(q, "order_no", req.OrderNo)
+	setInt(q, "offset", req.Offset)
+	setInt(q, "limit", req.Limit)
+	setInt(q, "create_time_order", req.CreateTimeOrder)
+	setTimestamp(q, "start_time", req.StartTime)
+	setTimestamp(q, "end_time", req.EndTime)
+	return do[model.TxDetailReq, model.TxDetailResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/wallets/%s/tx-details", c.projectID(req.BID), walletCode), q, nil)
 }
 
 // TxSummary 查询钱包交易记录概要
-func (c *ClientImpl) TxSummary(ctx context.Context, req *model.TxSummaryReq) (*model.TxSummaryResp, error) {
-	uri := fmt.Sprintf("/custody/v1/api/projects/%s/wallets/%s/tx-summaries", model.Bid, model.ETHWallet)
-	body, err := json.Marshal(*req)
-	if err != nil {
-		return nil, errors.New("json marshal fail")
+func (c *ClientImpl) TxSummary(ctx context.Context, req *model.TxSummaryReq) (_ *model.TxSummaryResp, err error) {
+	walletCode := c.walletCode(req.WalletCode)
+	ctx, span := c.startSpan(ctx, "TxSummary",
+		attrCoinName.String(req.CoinName),
+		attrWalletCode.String(walletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	q := url.Values{}
+	setString(q, "coin_name", req.CoinName)
+	setStrings(q, "tx_types", req.TxTypes)
+	setStrings(q, "addresses", req.Addresses)
+	setInt(q, "offset", req.Offset)
+	setInt(q, "limit", req.Limit)
+	setInt(q, "create_time_order", req.CreateTimeOrder)
+	setTimestamp(q, "start_time", req.StartTime)
+	setTimestamp(q, "end_time", req.EndTime)
+	return do[model.TxSummaryReq, model.TxSummaryResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/wallets/%s/tx-summaries", c.projectID(req.BID), walletCode), q, nil)
+}
+
+// GetAddressList 获取该钱包所有地址
+func (c *ClientImpl) GetAddressList(ctx context.Context, req *model.GetAddressesReq) (_ *model.GetAddressesResp, err error) {
+	walletCode := c.walletCode(req.WalletCode)
+	ctx, span := c.startSpan(ctx, "GetAddressList",
+		attrCoinName.String(req.CoinName),
+		attrWalletCode.String(walletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	q := url.Values{}
+	setString(q, "coin_name", req.CoinName)
+	if req.HideNoCoinAddress != nil {
+		setString(q, "hide_no_coin_address", *req.HideNoCoinAddress)
 	}
-	resp, err := c.buildRequest(ctx, http.MethodGet, uri, body)
-	if err != nil {
-		return nil, err
+	if req.KeyWord != nil {
+		setString(q, "key_word", *req.KeyWord)
 	}
-	var result model.TxSummaryResp
-	err = json.Unmarshal(resp, &result)
-	if err != nil {
-		return nil, errors.New("json unmarshal fail")
+	setInt(q, "offset", req.Offset)
+	setInt(q, "limit", req.Limit)
+	if req.SortByBalance != nil {
+		setString(q, "sort_by_balance", *req.SortByBalance)
 	}
-	return &result, nil
+	setInt64(q, "min_balance", req.MinBalance)
+	setInt64(q, "max_balance", req.MaxBalance)
+	setBool(q, "manage_wallet_address", req.ManageWalletAddress)
+	return do[model.GetAddressesReq, model.GetAddressesResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/wallets/%s/addresses", c.projectID(req.BID), walletCode), q, nil)
 }
 
-// GetAddressList 获取该钱包所有地址
-func (c *ClientImpl) GetAddressList(ctx context.Context, req *model.GetAddressesReq) (*model.GetAddressesResp, error) {
-	uri := fmt.Sprintf("/custody/v1/api/projects/%s/wallets/%s/addresses", model.Bid, model.ETHWallet)
-	body, err := json.Marshal(*req)
-	if err != nil {
-		return nil, errors.New("json marshal fail")
+// CreateAddresses 在钱包下批量生成新地址
+func (c *ClientImpl) CreateAddresses(ctx context.Context, req *model.CreateAddressesReq) (_ *model.CreateAddressesResp, err error) {
+	walletCode := c.walletCode(req.WalletCode)
+	ctx, span := c.startSpan(ctx, "CreateAddresses",
+		attrCoinName.String(req.CoinName),
+		attrWalletCode.String(walletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	if walletCode == "" {
+		return nil, errors.New("wallet_code is required")
+	}
+	if req.AddressNum <= 0 {
+		return nil, errors.New("address_num must be positive")
 	}
-	resp, err := c.buildRequest(ctx, http.MethodGet, uri, body)
+	return do[model.CreateAddressesReq, model.CreateAddressesResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/wallets/%s/addresses/apply", c.projectID(req.BID), walletCode), nil, req)
+}
+
+// UpdateAddressDescription 修改地址描述
+func (c *ClientImpl) UpdateAddressDescription(ctx context.Context, req *model.UpdateAddressDescriptionReq) (_ *model.UpdateAddressDescriptionResp, err error) {
+	walletCode := c.walletCode(req.WalletCode)
+	ctx, span := c.startSpan(ctx, "UpdateAddressDescription",
+		attrCoinName.String(req.CoinName),
+		attrWalletCode.String(walletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	if walletCode == "" {
+		return nil, errors.New("wallet_code is required")
+	}
+	if req.Address == "" {
+		return nil, errors.New("address is required")
+	}
+	return do[model.UpdateAddressDescriptionReq, model.UpdateAddressDescriptionResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/wallets/%s/addresses/%s/description", c.projectID(req.BID), walletCode, req.Address), nil, req)
+}
+
+// ListCoins 查询币种元数据，不带过滤条件的成功结果会刷新本地缓存
+func (c *ClientImpl) ListCoins(ctx context.Context, req *model.ListCoinsReq) (_ *model.ListCoinsResp, err error) {
+	ctx, span := c.startSpan(ctx, "ListCoins")
+	defer func() { endSpan(span, err) }()
+
+	q := url.Values{}
+	q.Set("b_id", c.projectID(req.BID))
+	setStrings(q, "coin_names", req.CoinNames)
+	setString(q, "chain", req.Chain)
+	result, err := do[model.ListCoinsReq, model.ListCoinsResp](ctx, c, http.MethodGet, "/custody/v1/api/coins", q, nil)
 	if err != nil {
 		return nil, err
 	}
-	var result model.GetAddressesResp
-	err = json.Unmarshal(resp, &result)
-	if err != nil {
-		return nil, errors.New("json unmarshal fail")
+	if len(req.CoinNames) == 0 && req.Chain == "" {
+		c.coins.set(result.Data, c.clock.Now())
 	}
+	return result, nil
+}
 
-	return &result, nil
+// ListWallets 分页查询业务线下的钱包
+func (c *ClientImpl) ListWallets(ctx context.Context, req *model.ListWalletsReq) (_ *model.ListWalletsResp, err error) {
+	ctx, span := c.startSpan(ctx, "ListWallets")
+	defer func() { endSpan(span, err) }()
+
+	q := url.Values{}
+	q.Set("b_id", c.projectID(req.BID))
+	setStrings(q, "coin_names", req.CoinNames)
+	setBool(q, "hide_no_coin_wallet", req.HideNoCoinWallet)
+	setInt(q, "offset", req.Offset)
+	setInt(q, "limit", req.Limit)
+	return do[model.ListWalletsReq, model.ListWalletsResp](ctx, c, http.MethodGet, "/custody/v1/api/wallets", q, nil)
+}
+
+// GetWallet 查询单个钱包详情
+func (c *ClientImpl) GetWallet(ctx context.Context, req *model.GetWalletReq) (_ *model.GetWalletResp, err error) {
+	walletCode := c.walletCode(req.WalletCode)
+	ctx, span := c.startSpan(ctx, "GetWallet", attrWalletCode.String(walletCode))
+	defer func() { endSpan(span, err) }()
+
+	q := url.Values{}
+	setStrings(q, "coin_names", req.CoinNames)
+	return do[model.GetWalletReq, model.GetWalletResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/wallets/%s", c.projectID(req.BID), walletCode), q, nil)
+}
+
+// ListWhitelist 查询提币白名单
+func (c *ClientImpl) ListWhitelist(ctx context.Context, req *model.ListWhitelistReq) (_ *model.ListWhitelistResp, err error) {
+	ctx, span := c.startSpan(ctx, "ListWhitelist", attrCoinName.String(req.CoinName))
+	defer func() { endSpan(span, err) }()
+
+	q := url.Values{}
+	setString(q, "coin_name", req.CoinName)
+	setString(q, "key_word", req.KeyWord)
+	setInt(q, "offset", req.Offset)
+	setInt(q, "limit", req.Limit)
+	return do[model.ListWhitelistReq, model.ListWhitelistResp](ctx, c, http.MethodGet,
+		pathf("/custody/v1/api/projects/%s/whitelist", c.projectID(req.BID)), q, nil)
+}
+
+// AddWhitelistAddresses 添加提币白名单地址
+func (c *ClientImpl) AddWhitelistAddresses(ctx context.Context, req *model.AddWhitelistAddressesReq) (_ *model.AddWhitelistAddressesResp, err error) {
+	ctx, span := c.startSpan(ctx, "AddWhitelistAddresses")
+	defer func() { endSpan(span, err) }()
+
+	// 白名单决定资金可以转到哪里，与提币一样受生产环境保护
+	if err := c.guardFundMoving("AddWhitelistAddresses"); err != nil {
+		return nil, err
+	}
+	return do[model.AddWhitelistAddressesReq, model.AddWhitelistAddressesResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/whitelist/add", c.projectID(req.BID)), nil, req)
+}
+
+// RemoveWhitelistAddresses 移除提币白名单地址
+func (c *ClientImpl) RemoveWhitelistAddresses(ctx context.Context, req *model.RemoveWhitelistAddressesReq) (_ *model.RemoveWhitelistAddressesResp, err error) {
+	ctx, span := c.startSpan(ctx, "RemoveWhitelistAddresses")
+	defer func() { endSpan(span, err) }()
+
+	// 白名单决定资金可以转到哪里，与提币一样受生产环境保护
+	if err := c.guardFundMoving("RemoveWhitelistAddresses"); err != nil {
+		return nil, err
+	}
+	return do[model.RemoveWhitelistAddressesReq, model.RemoveWhitelistAddressesResp](ctx, c, http.MethodPost,
+		pathf("/custody/v1/api/projects/%s/whitelist/remove", c.projectID(req.BID)), nil, req)
+}
+
+// Do 调用库中尚未封装的Cactus接口。path为不带域名的路径（如/custody/v1/api/...），
+// body为nil时不带请求体，否则按JSON编码；out为nil时丢弃响应，否则把完整响应JSON解码到out。
+// 返回码非0时返回*APIError。生产环境下除GET和只读接口外的调用都视为动用资金
+func (c *ClientImpl) Do(ctx context.Context, method, path string, query url.Values, body, out any) (err error) {
+	ctx, span := c.startSpan(ctx, "Do")
+	defer func() { endSpan(span, err) }()
+
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path %q must start with /", path)
+	}
+	if !strings.EqualFold(method, http.MethodGet) && !c.isReadOnlyPath(path) {
+		if err := c.guardFundMoving(method + " " + path); err != nil {
+			return err
+		}
+	}
+	var payload *any
+	if body != nil {
+		payload = &body
+	}
+	raw, err := do[any, json.RawMessage](ctx, c, method, path, query, payload)
+	if err != nil || out == nil {
+		return err
+	}
+	if err := json.Unmarshal(*raw, out); err != nil {
+		return fmt.Errorf("json unmarshal fail: %w", err)
+	}
+	return nil
 }
 
 // GetPublicIP 获取当前的公共 IP 地址（在白名单内的IP才可以访问Cactus）
-func (c *ClientImpl) GetPublicIP(ctx context.Context) (string, error) {
+func (c *ClientImpl) GetPublicIP(ctx context.Context) (_ string, err error) {
+	ctx, span := c.startSpan(ctx, "GetPublicIP")
+	defer func() { endSpan(span, err) }()
+
 	// 构造请求
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://ipconfig.io", nil)
 	if err != nil {
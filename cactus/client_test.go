@@ -0,0 +1,486 @@
+package cactus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-cactus/httpclient"
+	"go-cactus/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestClient 创建一个指向测试服务器、使用临时私钥的客户端
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *ClientImpl {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	opts = append([]Option{
+		WithBaseURL(server.URL),
+		WithPrivateKey(key),
+		WithHTTPOptions(httpclient.WithMaxRetries(0)),
+	}, opts...)
+	client, err := NewClient(opts...)
+	require.NoError(t, err)
+	return client.(*ClientImpl)
+}
+
+// TestNewClientMissingKey 测试未指定签名方式且无法加载私钥时NewClient返回错误
+func TestNewClientMissingKey(t *testing.T) {
+	client, err := NewClient()
+	assert.Nil(t, client)
+	assert.ErrorContains(t, err, "model.SIGN_PIRVATE_PATH")
+}
+
+// TestClientTracing 测试接口方法span、HTTP尝试子span以及traceparent传播
+func TestClientTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var traceparent string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "successful": true})
+	}, WithTracerProvider(tp))
+
+	_, err := client.CreateOrder(context.Background(), &model.CreateOrderReq{
+		CoinName: "USDT_SOL",
+		OrderNo:  "order-1",
+	})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	attempt, call := spans[0], spans[1]
+
+	assert.Equal(t, "HTTP POST", attempt.Name)
+	assert.Equal(t, "cactus.CreateOrder", call.Name)
+	assert.Equal(t, call.SpanContext.SpanID(), attempt.Parent.SpanID())
+	assert.Contains(t, traceparent, attempt.SpanContext.TraceID().String())
+	assert.Contains(t, traceparent, attempt.SpanContext.SpanID().String())
+
+	attrs := make(map[string]interface{})
+	for _, kv := range call.Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	assert.Equal(t, "USDT_SOL", attrs["cactus.coin_name"])
+	assert.Equal(t, "order-1", attrs["cactus.order_no"])
+	assert.Equal(t, int64(0), attrs["cactus.response.code"])
+	assert.Equal(t, "POST /custody/v1/api/projects//order/create", attrs["cactus.endpoint"])
+}
+
+// TestGetCoinInfoCache 测试币种元数据缓存与过期
+func TestGetCoinInfoCache(t *testing.T) {
+	clock := &fixedClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":0,"successful":true,"data":[{"coin_name":"USDT_SOL","chain":"SOL","decimals":6,"fee_coin_name":"SOL"}]}`))
+	}, WithClock(clock), WithCoinCacheTTL(time.Minute))
+
+	coin, err := client.GetCoinInfo(context.Background(), "USDT_SOL")
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), coin.Decimals)
+
+	_, err = client.GetCoinInfo(context.Background(), "BTC")
+	assert.ErrorIs(t, err, ErrCoinNotFound)
+	assert.Equal(t, 1, requests)
+
+	clock.t = clock.t.Add(time.Minute)
+	_, err = client.GetCoinInfo(context.Background(), "USDT_SOL")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+// TestAggregateBalances 测试多钱包余额汇总，未指定钱包时使用Profile中的钱包
+func TestAggregateBalances(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/wallets/hot"):
+			w.Write([]byte(`{"code":0,"data":{"wallet_code":"hot","coin_list":[
+				{"coin_name":"ETH","total_amount":"1.5","available_amount":"1","freeze_amount":"0.5"},
+				{"coin_name":"USDT_ETH","total_amount":"100","available_amount":"100","freeze_amount":"0"}]}}`))
+		case strings.HasSuffix(r.URL.Path, "/wallets/cold"):
+			w.Write([]byte(`{"code":0,"data":{"wallet_code":"cold","coin_list":[
+				{"coin_name":"ETH","total_amount":"10.25","available_amount":"10.25","freeze_amount":"0"}]}}`))
+		default:
+			w.Write([]byte(`{"code":404,"message":"no such wallet"}`))
+		}
+	}
+	client := newTestClient(t, handler)
+
+	report, err := AggregateBalances(context.Background(), client, "hot", "cold")
+	require.NoError(t, err)
+	require.Len(t, report.Wallets, 2)
+	require.Len(t, report.Totals, 2)
+	assert.Equal(t, "ETH", report.Totals[0].CoinName)
+	assert.Equal(t, "11.75", report.Totals[0].Total.String())
+	assert.Equal(t, "0.5", report.Totals[0].Frozen.String())
+	assert.Equal(t, "100", report.Totals[1].Total.String())
+
+	_, err = AggregateBalances(context.Background(), client, "missing")
+	assert.Error(t, err)
+
+	_, err = AggregateBalances(context.Background(), client)
+	assert.ErrorIs(t, err, ErrNoWallets)
+
+	profiled := newTestClient(t, handler, WithProfile(Profile{Name: Sandbox, ETHWallet: "cold"}))
+	report, err = AggregateBalances(context.Background(), profiled)
+	require.NoError(t, err)
+	require.Len(t, report.Wallets, 1)
+	assert.Equal(t, "cold", report.Wallets[0].WalletCode)
+}
+
+// TestGetAddressBalance 测试地址余额按字符串或数字解析都不丢失精度
+func TestGetAddressBalance(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"total":2,"list":[
+			{"address":"a1x","coin_name":"ETH","total_amount":1},
+			{"address":"a1","coin_name":"ETH","total_amount":1.123456789012345678,"available_amount":"1.123456789012345677","freeze_amount":"0.000000000000000001"}]}}`))
+	})
+
+	balance, err := client.GetAddressBalance(context.Background(), &model.GetAddressBalanceReq{Address: "a1", CoinName: "ETH"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.123456789012345678", balance.Total.String())
+	assert.Equal(t, "1.123456789012345677", balance.Available.String())
+	assert.Equal(t, "0.000000000000000001", balance.Frozen.String())
+
+	_, err = client.GetAddressBalance(context.Background(), &model.GetAddressBalanceReq{Address: "a2", CoinName: "ETH"})
+	assert.ErrorIs(t, err, ErrAddressNotFound)
+}
+
+// TestGetAddressBalancePaging 测试关键字模糊匹配的结果超过一页时，继续翻页查找完全一致的地址
+func TestGetAddressBalancePaging(t *testing.T) {
+	var offsets []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "a1", q.Get("key_word"))
+		offsets = append(offsets, q.Get("offset"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		list := make([]map[string]string, 0, limit)
+		for i := offset; i < min(offset+limit, 120); i++ {
+			address := fmt.Sprintf("a1%03d", i)
+			if i == 70 {
+				address = "a1"
+			}
+			list = append(list, map[string]string{"address": address, "coin_name": "ETH", "total_amount": "2"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{"total": 120, "list": list},
+		})
+	})
+
+	balance, err := client.GetAddressBalance(context.Background(), &model.GetAddressBalanceReq{Address: "a1", CoinName: "ETH"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", balance.Total.String())
+	assert.Equal(t, []string{"0", "50"}, offsets)
+}
+
+// TestWhitelistPrecheck 测试白名单预检查拦截非白名单地址
+func TestWhitelistPrecheck(t *testing.T) {
+	orders := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/whitelist") {
+			assert.Equal(t, "USDT_SOL", r.URL.Query().Get("coin_name"))
+			w.Write([]byte(`{"code":0,"data":{"total":1,"list":[{"coin_name":"USDT_SOL","address":"allowed"}]}}`))
+			return
+		}
+		orders++
+		w.Write([]byte(`{"code":0,"data":{"order_no":"1"}}`))
+	}, WithWhitelistPrecheck(true))
+
+	req := &model.CreateOrderReq{
+		CoinName:            "USDT_SOL",
+		DestAddressItemList: []model.DestAddressItem{{DestAddress: "allowed"}},
+	}
+	_, err := client.CreateOrder(context.Background(), req)
+	require.NoError(t, err)
+
+	req.DestAddressItemList = append(req.DestAddressItemList, model.DestAddressItem{DestAddress: "unknown"})
+	_, err = client.CreateOrder(context.Background(), req)
+	assert.ErrorIs(t, err, ErrNotWhitelisted)
+	assert.Equal(t, 1, orders)
+}
+
+// TestEachTxDetail 测试记录明细的查询参数与自动翻页
+func TestEachTxDetail(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		offset := r.URL.Query().Get("offset")
+		item := map[string]interface{}{"id": 1}
+		if offset != "0" {
+			item["id"] = 2
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{"total": 2, "list": []interface{}{item}},
+		})
+	})
+
+	req := model.TxDetailReq{BID: "b1", WalletCode: "w1", CoinName: "ETH"}
+	req.WithTimeRange(time.UnixMilli(1700000000000), time.Time{})
+	items, err := ListAllTxDetails(context.Background(), client, req)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, 2, items[1].ID)
+	require.Len(t, queries, 2)
+	assert.Equal(t, "coin_name=ETH&create_time_order=1&limit=50&offset=0&start_time=1700000000000&tx_types=WITHDRAW%2CDEPOSIT", queries[0])
+}
+
+// TestDoErrors 测试非0返回码转为APIError，解析失败时保留json错误
+func TestDoErrors(t *testing.T) {
+	body := `{"code":40001,"message":"order not found"}`
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/custody/v1/api/projects/b1/orders/a%2Fb", r.URL.EscapedPath())
+		w.Write([]byte(body))
+	})
+
+	_, err := client.GetOrder(context.Background(), &model.GetOrderReq{BID: "b1", OrderNo: "a/b"})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "GetOrder", apiErr.Operation)
+	assert.Equal(t, 40001, apiErr.Code)
+	assert.Equal(t, "order not found", apiErr.Message)
+
+	body = `{"code":0,"data":{"order_no":1}}`
+	_, err = client.GetOrder(context.Background(), &model.GetOrderReq{BID: "b1", OrderNo: "a/b"})
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+// TestDo 测试自定义接口调用与内置接口使用相同的签名和解析
+func TestDo(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/custody/v1/api/custom", r.URL.Path)
+		assert.Equal(t, "x=1", r.URL.RawQuery)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "api "))
+		assert.NotEmpty(t, r.Header.Get("Content-SHA256"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "v", body["k"])
+		w.Write([]byte(`{"code":0,"data":{"answer":42}}`))
+	})
+
+	var out model.Envelope[struct {
+		Answer int `json:"answer"`
+	}]
+	err := client.Do(context.Background(), http.MethodPost, "/custody/v1/api/custom",
+		url.Values{"x": {"1"}}, map[string]string{"k": "v"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 42, out.Data.Answer)
+
+	assert.Error(t, client.Do(context.Background(), http.MethodGet, "custody/v1/api/custom", nil, nil, nil))
+}
+
+// TestGetQueryParams 测试GET接口的参数放在查询串中，不带请求体
+func TestGetQueryParams(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Zero(t, r.ContentLength)
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Write([]byte(`{"code":0,"data":{"total":0,"list":[]}}`))
+	})
+
+	keyWord, limit, minBalance, manage := "0xabc", 20, int64(1000), true
+	_, err := client.GetAddressList(context.Background(), &model.GetAddressesReq{
+		BID: "b1", WalletCode: "w1", CoinName: "ETH",
+		KeyWord: &keyWord, Limit: &limit, MinBalance: &minBalance, ManageWalletAddress: &manage,
+	})
+	require.NoError(t, err)
+	req := &model.TxSummaryReq{BID: "b1", WalletCode: "w1", CoinName: "ETH", TxTypes: []string{"DEPOSIT"}}
+	req.WithTimeRange(time.UnixMilli(1700000000000), time.Time{})
+	_, err = client.TxSummary(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"/custody/v1/api/projects/b1/wallets/w1/addresses?coin_name=ETH&key_word=0xabc&limit=20&manage_wallet_address=true&min_balance=1000",
+		"/custody/v1/api/projects/b1/wallets/w1/tx-summaries?coin_name=ETH&start_time=1700000000000&tx_types=DEPOSIT",
+	}, queries)
+}
+
+// TestRemoveWhitelistAddressesData 测试移除白名单时data不是对象也能解析
+func TestRemoveWhitelistAddressesData(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"successful":true,"data":true}`))
+	})
+
+	resp, err := client.RemoveWhitelistAddresses(context.Background(), &model.RemoveWhitelistAddressesReq{BID: "b1"})
+	require.NoError(t, err)
+	assert.Equal(t, "true", string(resp.Data))
+}
+
+// TestListWallets 测试钱包列表的查询参数与自动翻页
+func TestListWallets(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/custody/v1/api/wallets", r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+		code := "w1"
+		if r.URL.Query().Get("offset") != "0" {
+			code = "w2"
+		}
+		w.Write([]byte(`{"code":0,"data":{"total":2,"list":[{"wallet_code":"` + code + `","wallet_type":"MIXED_ADDRESS"}]}}`))
+	})
+
+	hide := true
+	wallets, err := ListAllWallets(context.Background(), client, model.ListWalletsReq{
+		BID: "b1", CoinNames: []string{"ETH", "BTC"}, HideNoCoinWallet: &hide,
+	})
+	require.NoError(t, err)
+	require.Len(t, wallets, 2)
+	assert.Equal(t, "w2", wallets[1].WalletCode)
+	assert.Equal(t, []string{
+		"b_id=b1&coin_names=ETH%2CBTC&hide_no_coin_wallet=true&limit=50&offset=0",
+		"b_id=b1&coin_names=ETH%2CBTC&hide_no_coin_wallet=true&limit=50&offset=1",
+	}, queries)
+}
+
+// TestGetWallet 测试钱包详情的路径、查询参数和余额解析
+func TestGetWallet(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/custody/v1/api/projects/b1/wallets/w1", r.URL.Path)
+		assert.Equal(t, "coin_names=ETH", r.URL.RawQuery)
+		w.Write([]byte(`{"code":0,"data":{"wallet_code":"w1","wallet_type":"SEGREGATED_ADDRESS","coin_list":[
+			{"coin_name":"ETH","total_amount":"1.000000000000000001","available_amount":1,"freeze_amount":"0.000000000000000001"}]}}`))
+	})
+
+	resp, err := client.GetWallet(context.Background(), &model.GetWalletReq{BID: "b1", WalletCode: "w1", CoinNames: []string{"ETH"}})
+	require.NoError(t, err)
+	assert.Equal(t, "SEGREGATED_ADDRESS", resp.Data.WalletType)
+	require.Len(t, resp.Data.CoinList, 1)
+	assert.Equal(t, "1.000000000000000001", resp.Data.CoinList[0].TotalAmount.String())
+	assert.Equal(t, "1", resp.Data.CoinList[0].AvailableAmount.String())
+}
+
+// TestCreateAddresses 测试批量生成地址的路径、请求体和数量校验
+func TestCreateAddresses(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/custody/v1/api/projects/b1/wallets/w1/addresses/apply", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"coin_name": "BCH", "address_num": float64(2), "bch_address_format": "CashAddr"}, body)
+		w.Write([]byte(`{"code":0,"data":[{"address":"a1","coin_name":"BCH"},{"address":"a2","coin_name":"BCH"}]}`))
+	})
+
+	format := model.BCHAddressFormatCashAddr
+	req := &model.CreateAddressesReq{BID: "b1", WalletCode: "w1", CoinName: "BCH", AddressNum: 2, BCHAddressFormat: &format}
+	resp, err := client.CreateAddresses(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "a2", resp.Data[1].Address)
+
+	req.AddressNum = 0
+	_, err = client.CreateAddresses(context.Background(), req)
+	assert.EqualError(t, err, "address_num must be positive")
+
+	req.AddressNum, req.WalletCode = 2, ""
+	_, err = client.CreateAddresses(context.Background(), req)
+	assert.EqualError(t, err, "wallet_code is required")
+	assert.Equal(t, 1, requests)
+}
+
+// TestUpdateAddressDescription 测试修改地址描述时地址作为路径参数转义，请求体只含币种和描述，缺少路径参数时不发请求
+func TestUpdateAddressDescription(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/custody/v1/api/projects/b1/wallets/w1/addresses/bitcoincash:q%2Fp/description", r.URL.EscapedPath())
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"coin_name": "BCH", "description": "cold storage"}, body)
+		w.Write([]byte(`{"code":0,"data":{"address":"bitcoincash:q/p","description":"cold storage"}}`))
+	})
+
+	resp, err := client.UpdateAddressDescription(context.Background(), &model.UpdateAddressDescriptionReq{
+		BID: "b1", WalletCode: "w1", Address: "bitcoincash:q/p", CoinName: "BCH", Description: "cold storage",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cold storage", resp.Data.Description)
+
+	_, err = client.UpdateAddressDescription(context.Background(), &model.UpdateAddressDescriptionReq{
+		BID: "b1", WalletCode: "w1", CoinName: "BCH", Description: "cold storage",
+	})
+	assert.EqualError(t, err, "address is required")
+	_, err = client.UpdateAddressDescription(context.Background(), &model.UpdateAddressDescriptionReq{
+		BID: "b1", Address: "a1", CoinName: "BCH", Description: "cold storage",
+	})
+	assert.EqualError(t, err, "wallet_code is required")
+	assert.Equal(t, 1, requests)
+}
+
+// TestListOrders 测试订单列表的查询参数和响应解析
+func TestListOrders(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/custody/v1/api/projects/b1/orders", r.URL.Path)
+		assert.Equal(t, "coin_name=ETH&end_time=1700000999999&limit=10&start_time=1700000000000&status=AUDITING%2CAPPROVED&wallet_code=w1", r.URL.RawQuery)
+		assert.Zero(t, r.ContentLength)
+		w.Write([]byte(`{"code":0,"data":{"total":1,"list":[{"order_no":"o1","status":"AUDITING","tx_fee":"0.001","create_time_stamp":1700000000001}]}}`))
+	})
+
+	limit := 10
+	req := &model.ListOrdersReq{
+		BID:        "b1",
+		Statuses:   []model.OrderStatus{model.OrderStatusAuditing, model.OrderStatusApproved},
+		CoinName:   "ETH",
+		WalletCode: "w1",
+		Limit:      &limit,
+	}
+	req.WithTimeRange(time.UnixMilli(1700000000000), time.UnixMilli(1700001000000))
+	resp, err := client.ListOrders(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Data.List, 1)
+	order := resp.Data.List[0]
+	assert.Equal(t, model.OrderStatusAuditing, order.Status)
+	assert.True(t, order.Status.Cancellable())
+	assert.Equal(t, "0.001", order.TxFee.String())
+	assert.Equal(t, model.Timestamp(1700000000001), order.CreateTimeStamp)
+}
+
+// TestCancelOrder 测试取消订单时订单号作为路径参数，请求体只含取消原因
+func TestCancelOrder(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/custody/v1/api/projects/b1/orders/o1/cancel", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"reason": "duplicate"}, body)
+		w.Write([]byte(`{"code":0,"data":{"order_no":"o1","status":"CANCELLED"}}`))
+	})
+
+	reason := "duplicate"
+	resp, err := client.CancelOrder(context.Background(), &model.CancelOrderReq{BID: "b1", OrderNo: "o1", Reason: &reason})
+	require.NoError(t, err)
+	assert.Equal(t, "o1", resp.Data.OrderNo)
+	assert.Equal(t, model.OrderStatusCancelled, resp.Data.Status)
+	assert.True(t, resp.Data.Status.Final())
+}
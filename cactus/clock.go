@@ -0,0 +1,89 @@
+package cactus
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Clock 时间来源，测试时可注入固定或可控的时钟
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 使用本机时间
+type systemClock struct{}
+
+// Now 实现Clock
+func (systemClock) Now() time.Time { return time.Now() }
+
+const (
+	// defaultSkewThreshold 时钟偏差超过该值时打印告警
+	defaultSkewThreshold = 30 * time.Second
+	// skewSmoothing 指数平滑系数，越小越平稳
+	skewSmoothing = 0.2
+	// dateResolution Date响应头只精确到秒，服务端时间平均比头里的值晚半秒
+	dateResolution = 500 * time.Millisecond
+)
+
+// clockSkew 根据服务端Date响应头维护平滑后的本机时钟偏差（服务端时间 - 本机时间）
+type clockSkew struct {
+	mu        sync.Mutex
+	offset    time.Duration
+	samples   int
+	threshold time.Duration
+	exceeded  bool // 上一次观测后偏差是否超过阈值，用于只在跨越阈值时告警
+}
+
+// observe 用一次请求的发送/接收时间和服务端Date更新偏差，
+// 返回更新后的偏差，以及本次是否刚刚超过阈值
+func (s *clockSkew) observe(serverDate, sent, received time.Time) (time.Duration, bool) {
+	local := sent.Add(received.Sub(sent) / 2)
+	sample := serverDate.Add(dateResolution).Sub(local)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.samples == 0 {
+		s.offset = sample
+	} else {
+		s.offset += time.Duration(skewSmoothing * float64(sample-s.offset))
+	}
+	s.samples++
+
+	exceeded := s.offset > s.threshold || s.offset < -s.threshold
+	crossed := exceeded && !s.exceeded
+	s.exceeded = exceeded
+	return s.offset, crossed
+}
+
+// current 返回当前偏差
+func (s *clockSkew) current() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// now 返回校正后的当前时间，用于签名的Date请求头
+func (c *ClientImpl) now() time.Time {
+	if !c.skewCorrection {
+		return c.clock.Now()
+	}
+	return c.clock.Now().Add(c.skew.current())
+}
+
+// observeServerDate 从响应的Date头更新时钟偏差，偏差过大时告警
+func (c *ClientImpl) observeServerDate(resp *http.Response, sent, received time.Time) {
+	serverDate, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	offset, crossed := c.skew.observe(serverDate, sent, received)
+	if crossed {
+		c.logger.Printf("cactus[%s]: local clock is off by %s from server, Date header is corrected but the host clock should be synced", c.profile.Name, offset.Round(time.Second))
+	}
+}
+
+// ClockOffset 返回当前估算的时钟偏差（服务端时间 - 本机时间）
+func (c *ClientImpl) ClockOffset() time.Duration {
+	return c.skew.current()
+}
@@ -0,0 +1,59 @@
+package cactus
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-cactus/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock 固定返回同一时间的时钟
+type fixedClock struct{ t time.Time }
+
+func (f *fixedClock) Now() time.Time { return f.t }
+
+// TestClockSkewCorrection 测试根据服务端Date校正签名时间并告警
+func TestClockSkewCorrection(t *testing.T) {
+	local := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := local.Add(10 * time.Minute)
+
+	var dates []string
+	var logs bytes.Buffer
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		dates = append(dates, r.Header.Get("Date"))
+		w.Header().Set("Date", server.Format(http.TimeFormat))
+		w.Write([]byte(`{"code":0}`))
+	}, WithClock(&fixedClock{local}), WithLogger(log.New(&logs, "", 0)))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.CheckAddress(context.Background(), &model.CheckAddressReq{CoinName: "BTC"})
+		require.NoError(t, err)
+	}
+
+	require.Len(t, dates, 2)
+	assert.Equal(t, local.Format(model.TimeFormat), dates[0])
+	assert.Equal(t, server.Format(model.TimeFormat), dates[1])
+	assert.Equal(t, 10*time.Minute+dateResolution, client.ClockOffset())
+	assert.Contains(t, logs.String(), "local clock is off by 10m1s")
+	assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("\n")))
+}
+
+// TestClockSkewSmoothing 测试偏差的指数平滑
+func TestClockSkewSmoothing(t *testing.T) {
+	skew := clockSkew{threshold: time.Minute}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	offset, crossed := skew.observe(now.Add(10*time.Second), now, now)
+	assert.Equal(t, 10*time.Second+dateResolution, offset)
+	assert.False(t, crossed)
+
+	offset, _ = skew.observe(now, now, now)
+	assert.Equal(t, 8*time.Second+dateResolution, offset)
+}
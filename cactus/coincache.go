@@ -0,0 +1,69 @@
+package cactus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-cactus/model"
+)
+
+// defaultCoinCacheTTL 币种元数据缓存的默认有效期
+const defaultCoinCacheTTL = 10 * time.Minute
+
+// ErrCoinNotFound Cactus未返回该币种的元数据
+var ErrCoinNotFound = errors.New("coin not found")
+
+// coinCache 币种元数据的TTL缓存
+type coinCache struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	fetchedAt time.Time
+	coins     map[string]model.CoinInfo
+}
+
+// get 在缓存未过期时按币种名称查找
+func (cc *coinCache) get(name string, now time.Time) (model.CoinInfo, bool, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	if cc.coins == nil || now.Sub(cc.fetchedAt) >= cc.ttl {
+		return model.CoinInfo{}, false, false
+	}
+	coin, ok := cc.coins[name]
+	return coin, ok, true
+}
+
+// set 用完整的币种列表替换缓存
+func (cc *coinCache) set(coins []model.CoinInfo, now time.Time) {
+	m := make(map[string]model.CoinInfo, len(coins))
+	for _, coin := range coins {
+		m[coin.CoinName] = coin
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.coins = m
+	cc.fetchedAt = now
+}
+
+// GetCoinInfo 查询单个币种的元数据，优先使用缓存，缓存过期时重新拉取全部币种
+func (c *ClientImpl) GetCoinInfo(ctx context.Context, coinName string) (*model.CoinInfo, error) {
+	if coin, ok, fresh := c.coins.get(coinName, c.clock.Now()); fresh {
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCoinNotFound, coinName)
+		}
+		return &coin, nil
+	}
+
+	resp, err := c.ListCoins(ctx, &model.ListCoinsReq{})
+	if err != nil {
+		return nil, err
+	}
+	for _, coin := range resp.Data {
+		if coin.CoinName == coinName {
+			return &coin, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrCoinNotFound, coinName)
+}
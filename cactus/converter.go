@@ -9,9 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"go-cactus/model"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	pkcs "software.sslmate.com/src/go-pkcs12"
 	"sort"
 	"strings"
@@ -43,25 +43,36 @@ func PEMToECDSA(pemData []byte) (*ecdsa.PrivateKey, error) {
 	return nil, fmt.Errorf("不支持的PEM类型: %s", block.Type)
 }
 
-// InitPrivateKey 加载私钥
-func InitPrivateKey() *ecdsa.PrivateKey {
+// InitPrivateKey 从model.SIGN_PIRVATE_PATH加载私钥
+func InitPrivateKey() (*ecdsa.PrivateKey, error) {
+	key, err := LoadPKCS12(model.SIGN_PIRVATE_PATH, model.KEY_PASS)
+	if err != nil {
+		return nil, fmt.Errorf("load signing key from model.SIGN_PIRVATE_PATH: %w", err)
+	}
+	return key, nil
+}
+
+// LoadPKCS12 从PKCS12文件加载ECDSA私钥
+func LoadPKCS12(path, password string) (*ecdsa.PrivateKey, error) {
 	// 读取 PKCS12 文件
-	pfxData, err := ioutil.ReadFile(model.SIGN_PIRVATE_PATH) // 替换为你的 PKCS12 文件路径
+	pfxData, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Printf("无法读取 PKCS12 文件: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("无法读取 PKCS12 文件: %w", err)
 	}
+	return decodePKCS12(pfxData, password)
+}
+
+// decodePKCS12 解析PKCS12数据中的ECDSA私钥
+func decodePKCS12(pfxData []byte, password string) (*ecdsa.PrivateKey, error) {
 	// 解析 PKCS12 文件
-	privateKey, _, _, err := pkcs.DecodeChain(pfxData, model.KEY_PASS)
+	privateKey, _, _, err := pkcs.DecodeChain(pfxData, password)
 	if err != nil {
-		fmt.Printf("无法解析 PKCS12 文件: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("无法解析 PKCS12 文件: %w", err)
 	}
 	// 将私钥转换为 PEM 格式
 	privateKeyBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
 	if err != nil {
-		fmt.Printf("无法转换私钥为 PKCS8 格式: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("无法转换私钥为 PKCS8 格式: %w", err)
 	}
 	pemBlock := &pem.Block{
 		Type:  "PRIVATE KEY",
@@ -69,13 +80,12 @@ func InitPrivateKey() *ecdsa.PrivateKey {
 	}
 	pemData := pem.EncodeToMemory(pemBlock)
 	// 将PEM格式转化*ecdsa.PrivateKey类型
-	key, _ := PEMToECDSA(pemData)
-	return key
+	return PEMToECDSA(pemData)
 }
 
 // buildAuthorization 构造Authorization : api+ " " + AKId + ":" + Sign
-func buildAuthorization(sign string) string {
-	return fmt.Sprintf("api %s:%s", model.AK_ID, sign)
+func buildAuthorization(akID, sign string) string {
+	return fmt.Sprintf("api %s:%s", akID, sign)
 }
 
 // buildContentToSign 构造签名体(uri可携带参数)
@@ -88,7 +98,7 @@ func buildAuthorization(sign string) string {
 // "x-api-key:X5SGmgTAoYaVw1t7oD2p82pHgf0eNNVw3wxYGgM2\n" +
 // "x-api-nonce:36dbe33ed529455cb0638eef0f5f59e3\n" +
 // "/custody/v1/api/wallets?{b_id=[4a3e2fb40faa4b9d94480559ac01e8de], coin_names=[BTC,LTC], hide_no_coin_wallet=[false], total_market_order=[0]}"
-func buildContentToSign(method, uri, date, nonce string, body []byte) (string, error) {
+func buildContentToSign(method, uri, date, apiKey, nonce string, body []byte) (string, error) {
 	//先格式化URI
 	formatURI, err := formatURIParameters(uri)
 	if err != nil {
@@ -99,7 +109,7 @@ func buildContentToSign(method, uri, date, nonce string, body []byte) (string, e
 
 	if method == http.MethodGet {
 		ret = fmt.Sprintf("%s\napplication/json\n\napplication/json\n%s\nx-api-key:%s\nx-api-nonce:%s\n%s",
-			method, date, model.API_KEY, nonce, formatURI)
+			method, date, apiKey, nonce, formatURI)
 	} else {
 		var contentSHA string
 		if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
@@ -108,7 +118,7 @@ func buildContentToSign(method, uri, date, nonce string, body []byte) (string, e
 			contentSHA = ""
 		}
 		ret = fmt.Sprintf("%s\napplication/json\n%s\napplication/json\n%s\nx-api-key:%s\nx-api-nonce:%s\n%s",
-			method, contentSHA, date, model.API_KEY, nonce, formatURI)
+			method, contentSHA, date, apiKey, nonce, formatURI)
 	}
 
 	return ret, nil
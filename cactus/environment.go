@@ -0,0 +1,106 @@
+package cactus
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go-cactus/model"
+)
+
+// Environment 客户端所连接的环境
+type Environment string
+
+const (
+	Sandbox    Environment = "sandbox"    // 测试环境
+	Production Environment = "production" // 生产环境，动用资金的调用默认被拒绝
+	Custom     Environment = "custom"     // 自定义环境，未指定Profile时使用model中的配置
+)
+
+// ErrProductionGuard 生产环境未开启资金操作时拒绝动用资金的调用
+var ErrProductionGuard = errors.New("fund-moving call refused in production")
+
+// Profile 一个环境的完整配置，各环境使用各自的凭证、业务线和钱包
+type Profile struct {
+	Name       Environment // 环境名称，为空时视为Custom
+	BaseURL    string      // API地址，为空时不修改（默认model.URL_PRE）
+	APIKey     string      // custody发放的api key
+	Credential Credential  // 签名凭证，Signer为nil时用AKID加上model中配置的PKCS#12私钥
+	BID        string      // 业务线编号
+	SOLWallet  string      // SOL钱包编号
+	TronWallet string      // Tron钱包编号
+	ETHWallet  string      // ETH钱包编号，请求未指定钱包时的默认值
+}
+
+// defaultProfile 使用model中的常量，保持未配置Profile时的行为
+func defaultProfile() Profile {
+	return Profile{
+		Name:       Custom,
+		APIKey:     model.API_KEY,
+		Credential: Credential{AKID: model.AK_ID},
+		BID:        model.Bid,
+		SOLWallet:  model.SOLWallet,
+		TronWallet: model.TronWallet,
+		ETHWallet:  model.ETHWallet,
+	}
+}
+
+// WalletCodes 返回Profile中配置了的钱包编号
+func (p Profile) WalletCodes() []string {
+	var wallets []string
+	for _, code := range []string{p.SOLWallet, p.TronWallet, p.ETHWallet} {
+		if code != "" {
+			wallets = append(wallets, code)
+		}
+	}
+	return wallets
+}
+
+// Environment 返回客户端当前的环境
+func (c *ClientImpl) Environment() Environment {
+	return c.profile.Name
+}
+
+// WalletCodes 返回客户端Profile中配置了的钱包编号
+func (c *ClientImpl) WalletCodes() []string {
+	return c.profile.WalletCodes()
+}
+
+// readOnlyPaths 生产环境下允许通过Do调用的非GET接口（路径后缀），这些接口不会动用资金
+var readOnlyPaths = []string{
+	"/addresses/type/check",
+	"/order/estimate-fee",
+}
+
+// isProduction 是否按生产环境保护：显式的Production，或任何连接model.URL_PRE的环境，
+// 避免未设置BaseURL的Sandbox等Profile落到生产地址上动用真实资金
+func (c *ClientImpl) isProduction() bool {
+	return c.profile.Name == Production || strings.TrimSuffix(c.baseURL, "/") == model.URL_PRE
+}
+
+// guardFundMoving 生产环境未开启资金操作时拒绝动用资金的调用
+func (c *ClientImpl) guardFundMoving(op string) error {
+	if !c.isProduction() || c.productionFundMoves {
+		return nil
+	}
+	return fmt.Errorf("cactus[%s] %s: %w, enable it with WithProductionFundMoves(true)", c.profile.Name, op, ErrProductionGuard)
+}
+
+// isReadOnlyPath 判断自定义调用的非GET路径是否在内置或WithReadOnlyPaths指定的允许列表中，
+// 比较前去掉查询参数和末尾的/，忽略大小写
+func (c *ClientImpl) isReadOnlyPath(p string) bool {
+	p = normalizePath(p)
+	for _, allowed := range append(readOnlyPaths, c.readOnlyPaths...) {
+		if strings.HasSuffix(p, normalizePath(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePath 统一路径的写法，用于允许列表匹配
+func normalizePath(p string) string {
+	p, _, _ = strings.Cut(p, "?")
+	return strings.ToLower(path.Clean("/" + p))
+}
@@ -0,0 +1,156 @@
+package cactus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProfile 测试环境配置中的api key、业务线和默认钱包
+func TestProfile(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "sandbox-key", r.Header.Get("x-api-key"))
+		paths = append(paths, r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0})
+	}, WithProfile(Profile{Name: Sandbox, APIKey: "sandbox-key", BID: "sb", ETHWallet: "sb-eth"}))
+
+	assert.Equal(t, Sandbox, client.Environment())
+	_, err := client.GetAddressList(context.Background(), &model.GetAddressesReq{CoinName: "ETH"})
+	require.NoError(t, err)
+	_, err = client.TxDetail(context.Background(), &model.TxDetailReq{CoinName: "ETH"})
+	require.NoError(t, err)
+	_, err = client.GetWallet(context.Background(), &model.GetWalletReq{})
+	require.NoError(t, err)
+	_, err = client.CreateOrder(context.Background(), &model.CreateOrderReq{CoinName: "ETH"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/custody/v1/api/projects/sb/wallets/sb-eth/addresses",
+		"/custody/v1/api/projects/sb/wallets/sb-eth/tx-details",
+		"/custody/v1/api/projects/sb/wallets/sb-eth",
+		"/custody/v1/api/projects/sb/order/create",
+	}, paths)
+}
+
+// TestProductionGuard 测试生产环境默认拒绝动用资金的调用，错误中带有环境
+func TestProductionGuard(t *testing.T) {
+	var requests int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 500, "message": "boom"})
+	}
+	client := newTestClient(t, handler, WithProfile(Profile{Name: Production, BID: "prod"}))
+
+	_, err := client.CreateOrder(context.Background(), &model.CreateOrderReq{CoinName: "ETH"})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+	assert.Contains(t, err.Error(), "cactus[production] CreateOrder")
+	_, err = client.TransferBetweenWallets(context.Background(), &model.TransferReq{
+		FromWalletCode: "hot", ToWalletCode: "cold", CoinName: "ETH", Amount: decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+	err = client.Do(context.Background(), http.MethodPost, "/custody/v1/api/projects/prod/order/create", nil, struct{}{}, nil)
+	assert.ErrorIs(t, err, ErrProductionGuard)
+	_, err = client.AddWhitelistAddresses(context.Background(), &model.AddWhitelistAddressesReq{})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+	_, err = client.RemoveWhitelistAddresses(context.Background(), &model.RemoveWhitelistAddressesReq{})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+	assert.Zero(t, requests)
+
+	// 只读接口不受影响
+	_, err = client.GetOrder(context.Background(), &model.GetOrderReq{OrderNo: "o1"})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, Production, apiErr.Environment)
+	assert.Contains(t, err.Error(), "cactus[production] GetOrder failed")
+
+	enabled := newTestClient(t, handler, WithProfile(Profile{Name: Production}), WithProductionFundMoves(true))
+	_, err = enabled.CreateOrder(context.Background(), &model.CreateOrderReq{CoinName: "ETH"})
+	assert.NotErrorIs(t, err, ErrProductionGuard)
+	assert.Equal(t, 2, requests)
+}
+
+// TestDefaultHostGuard 测试未指定环境、连接model.URL_PRE的客户端按生产环境保护
+func TestDefaultHostGuard(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL.Path)
+	}, WithBaseURL(model.URL_PRE+"/"))
+
+	assert.Equal(t, Custom, client.Environment())
+	_, err := client.CreateOrder(context.Background(), &model.CreateOrderReq{CoinName: "ETH"})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+
+	sandbox := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0})
+	}, WithProfile(Profile{Name: Sandbox}))
+	_, err = sandbox.CreateOrder(context.Background(), &model.CreateOrderReq{CoinName: "ETH"})
+	assert.NoError(t, err)
+}
+
+// TestSandboxDefaultHostGuard 测试未设置BaseURL的Sandbox环境落到model.URL_PRE时同样按生产环境保护
+func TestSandboxDefaultHostGuard(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	client, err := NewClient(WithPrivateKey(key), WithProfile(Profile{Name: Sandbox, BID: "sb"}))
+	require.NoError(t, err)
+
+	_, err = client.CreateOrder(context.Background(), &model.CreateOrderReq{CoinName: "ETH"})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+	assert.Contains(t, err.Error(), "cactus[sandbox] CreateOrder")
+	_, err = client.AddWhitelistAddresses(context.Background(), &model.AddWhitelistAddressesReq{})
+	assert.ErrorIs(t, err, ErrProductionGuard)
+}
+
+// TestDoProductionGuard 测试生产环境下Do的非GET调用默认被拒绝，只读接口除外
+func TestDoProductionGuard(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0})
+	}, WithProfile(Profile{Name: Production}), WithReadOnlyPaths("/whitelist/list"))
+
+	for _, p := range []string{
+		"/custody/v1/api/projects/prod/order/create/",
+		"/custody/v1/api/projects/prod/ORDER/Create",
+		"/custody/v1/api/projects/prod/withdraw?x=1",
+	} {
+		err := client.Do(context.Background(), http.MethodPost, p, nil, struct{}{}, nil)
+		assert.ErrorIs(t, err, ErrProductionGuard, p)
+	}
+	assert.Empty(t, paths)
+
+	ctx := context.Background()
+	require.NoError(t, client.Do(ctx, http.MethodGet, "/custody/v1/api/projects/prod/orders", nil, nil, nil))
+	require.NoError(t, client.Do(ctx, http.MethodPost, "/custody/v1/api/projects/prod/order/estimate-fee/", nil, struct{}{}, nil))
+	require.NoError(t, client.Do(ctx, http.MethodPost, "/custody/v1/api/projects/prod/whitelist/list", nil, struct{}{}, nil))
+	assert.Equal(t, []string{
+		"GET /custody/v1/api/projects/prod/orders",
+		"POST /custody/v1/api/projects/prod/order/estimate-fee/",
+		"POST /custody/v1/api/projects/prod/whitelist/list",
+	}, paths)
+}
+
+// TestProfileAKIDOptionOrder 测试WithPrivateKey放在WithProfile之前时仍使用Profile的AK ID签名
+func TestProfileAKIDOptionOrder(t *testing.T) {
+	var auth string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": 0})
+	}, WithProfile(Profile{Name: Sandbox, Credential: Credential{AKID: "sb-ak"}}))
+
+	_, err := client.GetOrder(context.Background(), &model.GetOrderReq{OrderNo: "o1"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(auth, "api sb-ak:"), auth)
+	assert.Equal(t, "sb-ak", client.keys.Primary().AKID)
+}
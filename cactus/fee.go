@@ -0,0 +1,44 @@
+package cactus
+
+import (
+	"context"
+
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// fillFeeTotals 计算每个档位的收款合计和按币种的总花费，
+// 接口未返回手续费币种时从币种元数据缓存中查询，仍未知时Total为nil且Totals不含手续费
+func (c *ClientImpl) fillFeeTotals(ctx context.Context, req *model.EstimateFeeReq, estimates []model.FeeEstimate) {
+	amount := decimal.Zero
+	for _, item := range req.DestAddressItemList {
+		amount = amount.Add(item.Amount)
+	}
+
+	var defaultFeeCoin string
+	looked := false
+	for i := range estimates {
+		e := &estimates[i]
+		if e.FeeCoinName == "" {
+			if !looked {
+				if coin, err := c.GetCoinInfo(ctx, req.CoinName); err == nil {
+					defaultFeeCoin = coin.FeeCoinName
+				}
+				looked = true
+			}
+			e.FeeCoinName = defaultFeeCoin
+		}
+		e.Amount = amount
+		e.Totals = map[string]decimal.Decimal{req.CoinName: amount}
+		switch e.FeeCoinName {
+		case "":
+		case req.CoinName:
+			total := amount.Add(e.Fee)
+			e.Total = &total
+			e.Totals[req.CoinName] = total
+		default:
+			e.Totals[e.FeeCoinName] = e.Fee
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cactus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEstimateFee 测试预估请求的路径和请求体，以及按币种计算的总花费
+func TestEstimateFee(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/custody/v1/api/coins" {
+			w.Write([]byte(`{"code":0,"data":[{"coin_name":"USDT_ETH","fee_coin_name":"ETH"}]}`))
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/custody/v1/api/projects/b1/order/estimate-fee", r.URL.Path)
+		var req model.EstimateFeeReq
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "hot", req.FromWalletCode)
+		require.Len(t, req.DestAddressItemList, 2)
+
+		feeCoin := `"fee_coin_name":"ETH",`
+		if req.CoinName != "ETH" {
+			feeCoin = ""
+		}
+		w.Write([]byte(`{"code":0,"data":[
+			{"level":"SLOW",` + feeCoin + `"fee":"0.001"},
+			{"level":"FAST",` + feeCoin + `"fee":"0.003"}]}`))
+	})
+
+	estimate := func(coinName string) []model.FeeEstimate {
+		resp, err := client.EstimateFee(context.Background(), &model.EstimateFeeReq{
+			BID:            "b1",
+			FromWalletCode: "hot",
+			CoinName:       coinName,
+			DestAddressItemList: []model.DestAddressItem{
+				{DestAddress: "a", Amount: decimal.RequireFromString("1.5")},
+				{DestAddress: "b", Amount: decimal.RequireFromString("0.5")},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Data, 2)
+		return resp.Data
+	}
+
+	// 手续费币种与提币币种相同
+	eth := estimate("ETH")
+	assert.Equal(t, "2", eth[0].Amount.String())
+	require.NotNil(t, eth[1].Total)
+	assert.Equal(t, "2.003", eth[1].Total.String())
+	assert.Equal(t, map[string]string{"ETH": "2.003"}, totalStrings(eth[1].Totals))
+
+	// 代币的手续费计入元数据中的手续费币种
+	token := estimate("USDT_ETH")
+	assert.Equal(t, "ETH", token[0].FeeCoinName)
+	assert.Nil(t, token[0].Total)
+	assert.Equal(t, map[string]string{"USDT_ETH": "2", "ETH": "0.001"}, totalStrings(token[0].Totals))
+
+	// 手续费币种未知时不计入手续费
+	unknown := estimate("XYZ")
+	assert.Empty(t, unknown[0].FeeCoinName)
+	assert.Nil(t, unknown[0].Total)
+	assert.Equal(t, map[string]string{"XYZ": "2"}, totalStrings(unknown[0].Totals))
+}
+
+func totalStrings(totals map[string]decimal.Decimal) map[string]string {
+	out := make(map[string]string, len(totals))
+	for coin, total := range totals {
+		out[coin] = total.String()
+	}
+	return out
+}
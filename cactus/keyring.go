@@ -0,0 +1,217 @@
+package cactus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Signer 对签名体进行签名，返回Authorization中使用的签名串
+type Signer interface {
+	Sign(content string) (string, error)
+}
+
+// ECDSASigner 使用ECDSA私钥进行SHA256签名，输出base64编码的ASN.1 DER签名
+type ECDSASigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewECDSASigner 创建ECDSA签名器
+func NewECDSASigner(key *ecdsa.PrivateKey) *ECDSASigner {
+	return &ECDSASigner{key: key}
+}
+
+// Sign 实现Signer
+func (s *ECDSASigner) Sign(content string) (string, error) {
+	if s.key == nil {
+		return "", errors.New("private key is not loaded")
+	}
+	hashed := sha256.Sum256([]byte(content))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	// 将r和s转换为ASN.1 DER格式
+	type ecdsaSignature struct {
+		R, S *big.Int
+	}
+	sigAsn1, err := asn1.Marshal(ecdsaSignature{r, sig})
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sigAsn1), nil
+}
+
+// Credential 一组签名凭证：Cactus分配的AK ID及其对应私钥的签名器
+type Credential struct {
+	AKID   string
+	Signer Signer
+}
+
+// keyPair 主、备凭证，整体替换以保证切换是原子的
+type keyPair struct {
+	primary   Credential
+	secondary *Credential
+}
+
+// KeyRing 持有主、备两组凭证，支持运行时原子切换，用于密钥轮换
+type KeyRing struct {
+	pair atomic.Pointer[keyPair]
+}
+
+// NewKeyRing 创建密钥环，secondary可为nil
+func NewKeyRing(primary Credential, secondary *Credential) *KeyRing {
+	k := &KeyRing{}
+	k.Set(primary, secondary)
+	return k
+}
+
+// Set 原子地替换主、备凭证
+func (k *KeyRing) Set(primary Credential, secondary *Credential) {
+	k.pair.Store(&keyPair{primary: primary, secondary: secondary})
+}
+
+// Primary 返回主凭证
+func (k *KeyRing) Primary() Credential {
+	return k.pair.Load().primary
+}
+
+// Secondary 返回备用凭证
+func (k *KeyRing) Secondary() (Credential, bool) {
+	p := k.pair.Load()
+	if p.secondary == nil {
+		return Credential{}, false
+	}
+	return *p.secondary, true
+}
+
+// Promote 把备用凭证提升为主凭证，原主凭证降为备用，在Cactus侧完成新公钥登记后调用
+func (k *KeyRing) Promote() error {
+	for {
+		old := k.pair.Load()
+		if old.secondary == nil {
+			return errors.New("no secondary credential to promote")
+		}
+		previous := old.primary
+		next := &keyPair{primary: *old.secondary, secondary: &previous}
+		if k.pair.CompareAndSwap(old, next) {
+			return nil
+		}
+	}
+}
+
+// KeyLoader 从外部（通常是磁盘）加载主、备凭证
+type KeyLoader func() (primary Credential, secondary *Credential, err error)
+
+// Reload 调用loader重新加载凭证，加载失败时保留原有凭证
+func (k *KeyRing) Reload(load KeyLoader) error {
+	primary, secondary, err := load()
+	if err != nil {
+		return err
+	}
+	k.Set(primary, secondary)
+	return nil
+}
+
+// WatchSIGHUP 收到SIGHUP时重新加载凭证，直到ctx结束；加载失败时调用onErr
+func (k *KeyRing) WatchSIGHUP(ctx context.Context, load KeyLoader, onErr func(error)) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sig:
+				if err := k.Reload(load); err != nil && onErr != nil {
+					onErr(err)
+				}
+			}
+		}
+	}()
+}
+
+// WatchFiles 每隔interval检查paths的修改时间，有变化时重新加载凭证，直到ctx结束
+func (k *KeyRing) WatchFiles(ctx context.Context, interval time.Duration, load KeyLoader, onErr func(error), paths ...string) {
+	modTimes := func() map[string]time.Time {
+		m := make(map[string]time.Time, len(paths))
+		for _, p := range paths {
+			if info, err := os.Stat(p); err == nil {
+				m[p] = info.ModTime()
+			}
+		}
+		return m
+	}
+
+	go func() {
+		last := modTimes()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := modTimes()
+				changed := len(current) != len(last)
+				for p, t := range current {
+					if !last[p].Equal(t) {
+						changed = true
+					}
+				}
+				if !changed {
+					continue
+				}
+				last = current
+				if err := k.Reload(load); err != nil && onErr != nil {
+					onErr(err)
+				}
+			}
+		}
+	}()
+}
+
+// SourceKeyLoader 返回从私钥来源加载主、备凭证的KeyLoader，secondary为nil时只加载主凭证
+func SourceKeyLoader(primaryAKID string, primary KeySource, secondaryAKID string, secondary KeySource) KeyLoader {
+	return func() (Credential, *Credential, error) {
+		p, err := NewCredential(primaryAKID, primary)
+		if err != nil {
+			return Credential{}, nil, err
+		}
+		if secondary == nil {
+			return p, nil, nil
+		}
+		s, err := NewCredential(secondaryAKID, secondary)
+		if err != nil {
+			return Credential{}, nil, err
+		}
+		return p, &s, nil
+	}
+}
+
+// PKCS12KeyLoader 返回从PKCS12文件加载主、备凭证的KeyLoader，secondaryPath为空时只加载主凭证
+func PKCS12KeyLoader(primaryAKID, primaryPath, secondaryAKID, secondaryPath, password string) KeyLoader {
+	var secondary KeySource
+	if secondaryPath != "" {
+		secondary = PKCS12FileSource(secondaryPath, password)
+	}
+	return SourceKeyLoader(primaryAKID, PKCS12FileSource(primaryPath, password), secondaryAKID, secondary)
+}
+
+// isSignatureError 判断响应是否表示签名/AK ID被拒绝
+func isSignatureError(status int) bool {
+	return status == http.StatusUnauthorized
+}
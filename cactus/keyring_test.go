@@ -0,0 +1,61 @@
+package cactus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-cactus/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCredential 生成一组临时凭证
+func newTestCredential(t *testing.T, akID string) Credential {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return Credential{AKID: akID, Signer: NewECDSASigner(key)}
+}
+
+// TestSignatureFallback 测试主凭证被拒绝后使用备用凭证重发
+func TestSignatureFallback(t *testing.T) {
+	secondary := newTestCredential(t, "new-ak")
+	keys := NewKeyRing(newTestCredential(t, "old-ak"), &secondary)
+
+	var akIDs []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		akIDs = append(akIDs, strings.SplitN(strings.TrimPrefix(auth, "api "), ":", 2)[0])
+		if strings.HasPrefix(auth, "api old-ak:") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"code":0,"successful":true}`))
+	}, WithKeyRing(keys), WithSignatureFallback(true))
+
+	resp, err := client.CheckAddress(context.Background(), &model.CheckAddressReq{CoinName: "BTC"})
+	require.NoError(t, err)
+	assert.True(t, resp.Successful)
+	assert.Equal(t, []string{"old-ak", "new-ak"}, akIDs)
+}
+
+// TestKeyRingPromote 测试主备凭证切换
+func TestKeyRingPromote(t *testing.T) {
+	keys := NewKeyRing(newTestCredential(t, "old-ak"), nil)
+	assert.Error(t, keys.Promote())
+
+	secondary := newTestCredential(t, "new-ak")
+	keys.Set(keys.Primary(), &secondary)
+	require.NoError(t, keys.Promote())
+
+	assert.Equal(t, "new-ak", keys.Primary().AKID)
+	previous, ok := keys.Secondary()
+	require.True(t, ok)
+	assert.Equal(t, "old-ak", previous.AKID)
+}
@@ -0,0 +1,108 @@
+package cactus
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	pkcs "software.sslmate.com/src/go-pkcs12"
+)
+
+// certValidity PKCS12中自签名证书的有效期，Cactus只使用其中的私钥
+const certValidity = 10 * 365 * 24 * time.Hour
+
+// GenerateKey 生成P-256 ECDSA私钥
+func GenerateKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// EncodePEM 将私钥编码为PKCS#8 PEM
+func EncodePEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// EncodePKCS12 将私钥连同自签名证书编码为PKCS#12密钥库，可被InitPrivateKey读取。
+// PKCS#12只有一个密码同时保护密钥库和私钥，因此storePass为空时沿用keyPass，两者不同则报错
+func EncodePKCS12(key *ecdsa.PrivateKey, keyPass, storePass string) ([]byte, error) {
+	if storePass != "" && storePass != keyPass {
+		return nil, errors.New("PKCS#12 uses a single password: KEY_PASS and STORE_PASS must be equal")
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "go-cactus api key"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return pkcs.Modern.Encode(key, cert, nil, keyPass)
+}
+
+// PublicKeyBase64 返回公钥的X.509 SubjectPublicKeyInfo DER的base64编码，即上传给Cactus的格式
+func PublicKeyBase64(key *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// PublicKeyPEM 返回PEM格式的公钥
+func PublicKeyPEM(key *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// PublicKeyFingerprint 返回公钥DER的SHA256指纹（十六进制）
+func PublicKeyFingerprint(key *ecdsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadPrivateKeyFile 读取PEM、加密PEM或PKCS#12格式的私钥文件，
+// PEM按内容识别，其余按PKCS#12解析，password用于PKCS#12和加密PEM
+func LoadPrivateKeyFile(path, password string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,92 @@
+package cactus
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeyFileRoundTrip 测试生成的PEM和PKCS#12都能被加载回同一把私钥
+func TestKeyFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	key, err := GenerateKey()
+	require.NoError(t, err)
+
+	pemData, err := EncodePEM(key)
+	require.NoError(t, err)
+	pemPath := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(pemPath, pemData, 0o600))
+
+	p12Data, err := EncodePKCS12(key, "secret", "")
+	require.NoError(t, err)
+	p12Path := filepath.Join(dir, "key.p12")
+	require.NoError(t, os.WriteFile(p12Path, p12Data, 0o600))
+
+	fromPEM, err := LoadPrivateKeyFile(pemPath, "")
+	require.NoError(t, err)
+	assert.True(t, key.Equal(fromPEM))
+
+	fromP12, err := LoadPKCS12(p12Path, "secret")
+	require.NoError(t, err)
+	assert.True(t, key.Equal(fromP12))
+
+	_, err = EncodePKCS12(key, "secret", "other")
+	assert.Error(t, err)
+}
+
+// TestKeySources 测试环境变量和加密文件两种私钥来源
+func TestKeySources(t *testing.T) {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+	pemData, err := EncodePEM(key)
+	require.NoError(t, err)
+	p12Data, err := EncodePKCS12(key, "secret", "")
+	require.NoError(t, err)
+
+	t.Setenv("CACTUS_TEST_PEM", string(pemData))
+	t.Setenv("CACTUS_TEST_P12", base64.StdEncoding.EncodeToString(p12Data))
+	for _, name := range []string{"CACTUS_TEST_PEM", "CACTUS_TEST_P12"} {
+		loaded, err := EnvKeySource(name, "secret").Load()
+		require.NoError(t, err, name)
+		assert.True(t, key.Equal(loaded), name)
+	}
+	_, err = EnvKeySource("CACTUS_TEST_MISSING", "").Load()
+	assert.Error(t, err)
+
+	encrypted, err := EncryptPEM(pemData, []byte("passphrase"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.enc.pem")
+	require.NoError(t, os.WriteFile(path, encrypted, 0o600))
+
+	loaded, err := EncryptedPEMFileSource(path, StaticPassphrase("passphrase")).Load()
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	_, err = EncryptedPEMFileSource(path, StaticPassphrase("wrong")).Load()
+	assert.Error(t, err)
+}
+
+// TestDecryptPEMScryptLimits 测试文件头中超出上限的scrypt参数在派生密钥前被拒绝
+func TestDecryptPEMScryptLimits(t *testing.T) {
+	encrypted, err := EncryptPEM([]byte("key"), []byte("passphrase"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(encrypted)
+	require.NotNil(t, block)
+
+	for _, params := range [][3]string{
+		{"2097152", "8", "1"},  // N超过2^20
+		{"1048576", "16", "1"}, // 内存超过1GiB
+		{"32768", "8", "1024"}, // 并行度过高
+		{"1000", "8", "1"},     // N不是2的幂
+		{"32768", "0", "1"},
+	} {
+		block.Headers["N"], block.Headers["R"], block.Headers["P"] = params[0], params[1], params[2]
+		_, err := DecryptPEM(pem.EncodeToMemory(block), []byte("passphrase"))
+		assert.ErrorContains(t, err, "scrypt parameters", params)
+	}
+}
@@ -0,0 +1,259 @@
+package cactus
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// EncryptedPEMType EncryptPEM输出的PEM块类型
+const EncryptedPEMType = "CACTUS ENCRYPTED PRIVATE KEY"
+
+// scrypt参数，N=2^15约需32MB内存
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+// 解密时接受的scrypt参数上限，防止构造的文件耗尽内存和CPU
+const (
+	maxScryptN      = 1 << 20 // N上限
+	maxScryptP      = 16      // 并行度上限，耗时与N·r·p成正比
+	maxScryptMemory = 1 << 30 // 128·N·r字节的内存上限
+)
+
+// KeySource 私钥来源，加载失败时返回错误而不是nil私钥
+type KeySource interface {
+	Load() (*ecdsa.PrivateKey, error)
+}
+
+// KeySourceFunc 函数形式的KeySource
+type KeySourceFunc func() (*ecdsa.PrivateKey, error)
+
+// Load 实现KeySource
+func (f KeySourceFunc) Load() (*ecdsa.PrivateKey, error) { return f() }
+
+// PassphraseFunc 获取口令，用于解密私钥文件
+type PassphraseFunc func() ([]byte, error)
+
+// StaticPassphrase 返回固定口令
+func StaticPassphrase(passphrase string) PassphraseFunc {
+	return func() ([]byte, error) { return []byte(passphrase), nil }
+}
+
+// EnvPassphrase 从环境变量读取口令
+func EnvPassphrase(name string) PassphraseFunc {
+	return func() ([]byte, error) {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", name)
+		}
+		return []byte(v), nil
+	}
+}
+
+// PromptPassphrase 在终端上提示输入口令（不回显），用于交互式命令行
+func PromptPassphrase(prompt string) PassphraseFunc {
+	return func() ([]byte, error) {
+		fd := int(os.Stdin.Fd())
+		if !term.IsTerminal(fd) {
+			return nil, errors.New("cannot prompt for passphrase: stdin is not a terminal")
+		}
+		fmt.Fprint(os.Stderr, prompt)
+		passphrase, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("read passphrase: %w", err)
+		}
+		return passphrase, nil
+	}
+}
+
+// PKCS12FileSource 从PKCS#12文件加载私钥
+func PKCS12FileSource(path, password string) KeySource {
+	return KeySourceFunc(func() (*ecdsa.PrivateKey, error) {
+		return LoadPKCS12(path, password)
+	})
+}
+
+// EnvKeySource 从环境变量加载私钥，取值可以是PEM文本，或base64编码的PEM/PKCS#12，
+// password用于PKCS#12
+func EnvKeySource(name, password string) KeySource {
+	return KeySourceFunc(func() (*ecdsa.PrivateKey, error) {
+		v, ok := os.LookupEnv(name)
+		if !ok || strings.TrimSpace(v) == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", name)
+		}
+		if strings.HasPrefix(strings.TrimSpace(v), "-----BEGIN") {
+			return PEMToECDSA([]byte(v))
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("environment variable %s is neither PEM nor base64: %w", name, err)
+		}
+		key, err := parsePrivateKey(data, password)
+		if err != nil {
+			return nil, fmt.Errorf("environment variable %s: %w", name, err)
+		}
+		return key, nil
+	})
+}
+
+// EncryptedPEMFileSource 从EncryptPEM加密的文件加载私钥
+func EncryptedPEMFileSource(path string, passphrase PassphraseFunc) KeySource {
+	return KeySourceFunc(func() (*ecdsa.PrivateKey, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		pass, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		plain, err := DecryptPEM(data, pass)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return PEMToECDSA(plain)
+	})
+}
+
+// NewCredential 从私钥来源加载私钥并生成凭证
+func NewCredential(akID string, src KeySource) (Credential, error) {
+	key, err := src.Load()
+	if err != nil {
+		return Credential{}, fmt.Errorf("load key for ak_id %s: %w", akID, err)
+	}
+	return Credential{AKID: akID, Signer: NewECDSASigner(key)}, nil
+}
+
+// parsePrivateKey 解析PEM、加密PEM或PKCS#12格式的私钥数据，password用于PKCS#12和加密PEM
+func parsePrivateKey(data []byte, password string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return decodePKCS12(data, password)
+	}
+	if block.Type == EncryptedPEMType {
+		plain, err := DecryptPEM(data, []byte(password))
+		if err != nil {
+			return nil, err
+		}
+		return PEMToECDSA(plain)
+	}
+	return PEMToECDSA(data)
+}
+
+// EncryptPEM 用scrypt派生的密钥以AES-256-GCM加密PEM私钥，
+// 输出自描述的PEM块，KDF参数、盐和nonce放在PEM头中
+func EncryptPEM(pemData, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type: EncryptedPEMType,
+		Headers: map[string]string{
+			"KDF":    "scrypt",
+			"N":      strconv.Itoa(scryptN),
+			"R":      strconv.Itoa(scryptR),
+			"P":      strconv.Itoa(scryptP),
+			"Salt":   hex.EncodeToString(salt),
+			"Cipher": "AES-256-GCM",
+			"Nonce":  hex.EncodeToString(nonce),
+		},
+		Bytes: gcm.Seal(nil, nonce, pemData, nil),
+	}), nil
+}
+
+// DecryptPEM 解密EncryptPEM的输出，返回原始PEM私钥
+func DecryptPEM(data, passphrase []byte) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != EncryptedPEMType {
+		return nil, errors.New("not an encrypted private key file")
+	}
+	h := block.Headers
+	if h["KDF"] != "scrypt" || h["Cipher"] != "AES-256-GCM" {
+		return nil, fmt.Errorf("unsupported encryption %s/%s", h["KDF"], h["Cipher"])
+	}
+	var params [3]int
+	for i, name := range []string{"N", "R", "P"} {
+		v, err := strconv.Atoi(h[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrypt parameter %s: %w", name, err)
+		}
+		params[i] = v
+	}
+	salt, err := hex.DecodeString(h["Salt"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	nonce, err := hex.DecodeString(h["Nonce"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	if err := checkScryptParams(params[0], params[1], params[2]); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt, params[0], params[1], params[2])
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce length")
+	}
+	plain, err := gcm.Open(nil, nonce, block.Bytes, nil)
+	if err != nil {
+		return nil, errors.New("decryption failed: wrong passphrase or corrupted file")
+	}
+	return plain, nil
+}
+
+// checkScryptParams 检查文件头中的scrypt参数在允许范围内
+func checkScryptParams(n, r, p int) error {
+	if n <= 1 || n&(n-1) != 0 || r <= 0 || p <= 0 {
+		return fmt.Errorf("invalid scrypt parameters N=%d R=%d P=%d", n, r, p)
+	}
+	if n > maxScryptN || p > maxScryptP || r > maxScryptMemory/128/n {
+		return fmt.Errorf("scrypt parameters N=%d R=%d P=%d exceed the allowed cost", n, r, p)
+	}
+	return nil
+}
+
+// newGCM 用scrypt从口令派生AES-256密钥并创建GCM
+func newGCM(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
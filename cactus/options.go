@@ -0,0 +1,141 @@
+package cactus
+
+import (
+	"crypto/ecdsa"
+	"log"
+	"time"
+
+	"go-cactus/httpclient"
+	"go-cactus/metrics"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option 定义Cactus客户端的可选配置
+type Option func(*ClientImpl)
+
+// WithBaseURL 设置Cactus API地址，默认使用model.URL_PRE
+func WithBaseURL(baseURL string) Option {
+	return func(c *ClientImpl) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithPrivateKey 直接指定签名私钥，不再从model.SIGN_PIRVATE_PATH加载。
+// AK ID在所有配置应用后取Profile中的AK ID，与配置的先后顺序无关
+func WithPrivateKey(key *ecdsa.PrivateKey) Option {
+	return func(c *ClientImpl) {
+		c.signer = NewECDSASigner(key)
+		c.keys = nil
+	}
+}
+
+// WithKeyRing 使用密钥环中的主、备凭证签名，可在运行时轮换
+func WithKeyRing(keys *KeyRing) Option {
+	return func(c *ClientImpl) {
+		c.keys = keys
+		c.signer = nil
+	}
+}
+
+// WithSignatureFallback 主凭证签名被拒绝（HTTP 401）时用备用凭证重发一次
+func WithSignatureFallback(enabled bool) Option {
+	return func(c *ClientImpl) {
+		c.signatureFallback = enabled
+	}
+}
+
+// WithHTTPOptions 追加底层HTTP客户端的配置
+func WithHTTPOptions(opts ...httpclient.Option) Option {
+	return func(c *ClientImpl) {
+		c.httpOpts = append(c.httpOpts, opts...)
+	}
+}
+
+// WithTracerProvider 开启链路追踪：每个接口方法创建一个span，
+// 底层HTTP客户端的每次请求尝试创建子span并传播W3C Trace Context
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *ClientImpl) {
+		c.tracer = tp.Tracer(tracerName)
+		c.httpOpts = append(c.httpOpts, httpclient.WithTracerProvider(tp))
+	}
+}
+
+// WithMetrics 开启指标采集：接口调用、HTTP状态码、Cactus返回码、重试与签名耗时
+func WithMetrics(m *metrics.ClientMetrics) Option {
+	return func(c *ClientImpl) {
+		c.metrics = m
+		c.httpOpts = append(c.httpOpts, httpclient.WithMetrics(m))
+	}
+}
+
+// WithLogger 设置日志输出，默认使用log.Default()
+func WithLogger(logger *log.Logger) Option {
+	return func(c *ClientImpl) {
+		c.logger = logger
+	}
+}
+
+// WithClock 设置时间来源，主要用于测试
+func WithClock(clock Clock) Option {
+	return func(c *ClientImpl) {
+		c.clock = clock
+	}
+}
+
+// WithClockSkewThreshold 设置时钟偏差告警阈值，默认30秒
+func WithClockSkewThreshold(threshold time.Duration) Option {
+	return func(c *ClientImpl) {
+		c.skew.threshold = threshold
+	}
+}
+
+// WithClockSkewCorrection 设置是否用服务端Date头估算的偏差校正签名时间，默认开启
+func WithClockSkewCorrection(enabled bool) Option {
+	return func(c *ClientImpl) {
+		c.skewCorrection = enabled
+	}
+}
+
+// WithCoinCacheTTL 设置币种元数据缓存的有效期，默认10分钟，0表示不缓存
+func WithCoinCacheTTL(ttl time.Duration) Option {
+	return func(c *ClientImpl) {
+		c.coins.ttl = ttl
+	}
+}
+
+// WithProfile 使用指定环境的配置（API地址、api key、凭证、业务线和钱包）。
+// WithPrivateKey、WithKeyRing指定的签名方式优先于Profile中的Signer；
+// WithBaseURL放在WithProfile之后时覆盖Profile中的API地址
+func WithProfile(p Profile) Option {
+	return func(c *ClientImpl) {
+		if p.Name == "" {
+			p.Name = Custom
+		}
+		c.profile = p
+		if p.BaseURL != "" {
+			c.baseURL = p.BaseURL
+		}
+	}
+}
+
+// WithProductionFundMoves 允许在生产环境调用CreateOrder等动用资金的接口，默认拒绝
+func WithProductionFundMoves(enabled bool) Option {
+	return func(c *ClientImpl) {
+		c.productionFundMoves = enabled
+	}
+}
+
+// WithReadOnlyPaths 声明不会动用资金的非GET接口（路径后缀，如/whitelist/list），生产环境下可以通过Do调用
+func WithReadOnlyPaths(paths ...string) Option {
+	return func(c *ClientImpl) {
+		c.readOnlyPaths = append(c.readOnlyPaths, paths...)
+	}
+}
+
+// WithWhitelistPrecheck 创建提币订单前检查每个收款地址（及memo）都在白名单内，不在时不提交订单
+func WithWhitelistPrecheck(enabled bool) Option {
+	return func(c *ClientImpl) {
+		c.whitelistPrecheck = enabled
+	}
+}
@@ -0,0 +1,85 @@
+package cactus
+
+import (
+	"context"
+
+	"go-cactus/model"
+)
+
+// defaultPageSize 自动翻页时每页的数量
+const defaultPageSize = 50
+
+// defaultTxTypes 未指定TxTypes时查询的记录类型
+var defaultTxTypes = []string{"WITHDRAW", "DEPOSIT"}
+
+// eachPage 按offset自动翻页，fetch查询一页（返回码非0时由fetch返回*APIError），fn返回错误时停止
+func eachPage[T any](fetch func(offset, limit int) (*model.Envelope[model.Page[T]], error), fn func(T) error) error {
+	offset, limit := 0, defaultPageSize
+	for {
+		resp, err := fetch(offset, limit)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Data.List {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		offset += len(resp.Data.List)
+		if len(resp.Data.List) == 0 || offset >= resp.Data.Total {
+			return nil
+		}
+	}
+}
+
+// listAll 取出全部分页数据
+func listAll[T any](fetch func(offset, limit int) (*model.Envelope[model.Page[T]], error)) ([]T, error) {
+	var items []T
+	err := eachPage(fetch, func(item T) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListAllWallets 自动翻页取出业务线下的全部钱包，req中的Offset和Limit会被覆盖
+func ListAllWallets(ctx context.Context, client Client, req model.ListWalletsReq) ([]model.WalletInfo, error) {
+	return listAll(func(offset, limit int) (*model.ListWalletsResp, error) {
+		req.Offset, req.Limit = &offset, &limit
+		return client.ListWallets(ctx, &req)
+	})
+}
+
+// EachTxDetail 按创建时间升序自动翻页遍历钱包记录明细，req中的Offset、Limit和CreateTimeOrder会被覆盖，fn返回错误时停止
+func EachTxDetail(ctx context.Context, client Client, req model.TxDetailReq, fn func(model.TxDetailItem) error) error {
+	asc := 1
+	return eachPage(func(offset, limit int) (*model.TxDetailResp, error) {
+		req.Offset, req.Limit, req.CreateTimeOrder = &offset, &limit, &asc
+		return client.TxDetail(ctx, &req)
+	}, fn)
+}
+
+// ListAllTxDetails 取出满足条件的全部钱包记录明细
+func ListAllTxDetails(ctx context.Context, client Client, req model.TxDetailReq) ([]model.TxDetailItem, error) {
+	var items []model.TxDetailItem
+	err := EachTxDetail(ctx, client, req, func(item model.TxDetailItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// EachTxSummary 按创建时间升序自动翻页遍历钱包交易记录概要，req中的Offset、Limit和CreateTimeOrder会被覆盖，fn返回错误时停止
+func EachTxSummary(ctx context.Context, client Client, req model.TxSummaryReq, fn func(model.TxSummaryItem) error) error {
+	asc := 1
+	return eachPage(func(offset, limit int) (*model.TxSummaryResp, error) {
+		req.Offset, req.Limit, req.CreateTimeOrder = &offset, &limit, &asc
+		return client.TxSummary(ctx, &req)
+	}, fn)
+}
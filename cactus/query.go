@@ -0,0 +1,75 @@
+package cactus
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go-cactus/model"
+)
+
+// projectID 返回请求中的业务线ID，为空时使用当前环境的业务线
+func (c *ClientImpl) projectID(bid string) string {
+	if bid != "" {
+		return bid
+	}
+	return c.profile.BID
+}
+
+// walletCode 返回请求中的钱包编号，为空时使用当前环境的ETH钱包
+func (c *ClientImpl) walletCode(code string) string {
+	if code != "" {
+		return code
+	}
+	return c.profile.ETHWallet
+}
+
+// withQuery 把查询参数拼到uri上，参数会按formatURIParameters的规则参与签名
+func withQuery(uri string, q url.Values) string {
+	if len(q) == 0 {
+		return uri
+	}
+	return uri + "?" + q.Encode()
+}
+
+// setString 非空时设置字符串参数
+func setString(q url.Values, key, value string) {
+	if value != "" {
+		q.Set(key, value)
+	}
+}
+
+// setStrings 非空时设置以逗号分隔的列表参数
+func setStrings(q url.Values, key string, values []string) {
+	if len(values) > 0 {
+		q.Set(key, strings.Join(values, ","))
+	}
+}
+
+// setInt 非nil时设置整型参数
+func setInt(q url.Values, key string, value *int) {
+	if value != nil {
+		q.Set(key, strconv.Itoa(*value))
+	}
+}
+
+// setInt64 非nil时设置长整型参数
+func setInt64(q url.Values, key string, value *int64) {
+	if value != nil {
+		q.Set(key, strconv.FormatInt(*value, 10))
+	}
+}
+
+// setTimestamp 非nil时设置毫秒时间戳参数
+func setTimestamp(q url.Values, key string, value *model.Timestamp) {
+	if value != nil {
+		q.Set(key, strconv.FormatInt(int64(*value), 10))
+	}
+}
+
+// setBool 非nil时设置布尔参数
+func setBool(q url.Values, key string, value *bool) {
+	if value != nil {
+		q.Set(key, strconv.FormatBool(*value))
+	}
+}
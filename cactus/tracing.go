@@ -0,0 +1,64 @@
+package cactus
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName 本包创建span时使用的instrumentation名称
+const tracerName = "go-cactus/cactus"
+
+// span属性名
+const (
+	attrEndpoint   = attribute.Key("cactus.endpoint")
+	attrCoinName   = attribute.Key("cactus.coin_name")
+	attrOrderNo    = attribute.Key("cactus.order_no")
+	attrWalletCode = attribute.Key("cactus.wallet_code")
+	attrRespCode   = attribute.Key("cactus.response.code")
+	attrEnv        = attribute.Key("cactus.environment")
+)
+
+// operationKey 在ctx中保存当前调用的接口方法名
+type operationKey struct{}
+
+// operationFromContext 取出当前调用的接口方法名，用作指标的endpoint标签
+func operationFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
+
+// startSpan 为一次接口调用创建span，name为Client接口的方法名，同时记入ctx供指标使用
+func (c *ClientImpl) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = context.WithValue(ctx, operationKey{}, name)
+	return c.tracer.Start(ctx, "cactus."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrEnv.String(string(c.profile.Name))),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan 记录错误并结束span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recordResponse 把接口地址和Cactus返回码记录到当前span上
+func recordResponse(ctx context.Context, method, uri string, code int, hasCode bool) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	path, _, _ := strings.Cut(uri, "?")
+	span.SetAttributes(attrEndpoint.String(method + " " + path))
+	if hasCode {
+		span.SetAttributes(attrRespCode.Int(code))
+	}
+}
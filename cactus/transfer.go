@@ -0,0 +1,87 @@
+package cactus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-cactus/model"
+)
+
+// TransferBetweenWallets 在自有钱包之间转账，实际通过CreateOrder提币到入账钱包的地址完成
+func (c *ClientImpl) TransferBetweenWallets(ctx context.Context, req *model.TransferReq) (_ *model.CreateOrderResp, err error) {
+	ctx, span := c.startSpan(ctx, "TransferBetweenWallets",
+		attrCoinName.String(req.CoinName),
+		attrOrderNo.String(req.OrderNo),
+		attrWalletCode.String(req.FromWalletCode),
+	)
+	defer func() { endSpan(span, err) }()
+
+	if err := c.guardFundMoving("TransferBetweenWallets"); err != nil {
+		return nil, err
+	}
+	if req.FromWalletCode == "" || req.ToWalletCode == "" {
+		return nil, errors.New("from and to wallet codes are required")
+	}
+	if req.FromWalletCode == req.ToWalletCode {
+		return nil, errors.New("from and to wallet must differ")
+	}
+	if !req.Amount.IsPositive() {
+		return nil, errors.New("transfer amount must be positive")
+	}
+
+	toAddress := req.ToAddress
+	if toAddress == "" {
+		toAddress, err = c.resolveWalletAddress(ctx, req.BID, req.ToWalletCode, req.CoinName)
+	} else {
+		err = c.checkWalletAddress(ctx, req.BID, req.ToWalletCode, req.CoinName, toAddress)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	remark := fmt.Sprintf("internal transfer from %s to %s", req.FromWalletCode, req.ToWalletCode)
+	return c.CreateOrder(ctx, &model.CreateOrderReq{
+		BID:            req.BID,
+		FromWalletCode: req.FromWalletCode,
+		CoinName:       req.CoinName,
+		OrderNo:        req.OrderNo,
+		Description:    req.Description,
+		FeeRateLevel:   req.FeeRateLevel,
+		DestAddressItemList: []model.DestAddressItem{{
+			DestAddress: toAddress,
+			Amount:      req.Amount,
+			Remark:      &remark,
+		}},
+	})
+}
+
+// resolveWalletAddress 取钱包下该币种的第一个地址作为入账地址
+func (c *ClientImpl) resolveWalletAddress(ctx context.Context, bid, walletCode, coinName string) (string, error) {
+	limit := 1
+	resp, err := c.GetAddressList(ctx, &model.GetAddressesReq{
+		BID:        bid,
+		WalletCode: walletCode,
+		CoinName:   coinName,
+		Limit:      &limit,
+	})
+	if err != nil {
+		return "", fmt.Errorf("resolve address of wallet %s: %w", walletCode, err)
+	}
+	if len(resp.Data.List) == 0 {
+		return "", fmt.Errorf("wallet %s has no %s address", walletCode, coinName)
+	}
+	return resp.Data.List[0].Address, nil
+}
+
+// checkWalletAddress 确认入账地址属于入账钱包，避免内部转账转到外部地址
+func (c *ClientImpl) checkWalletAddress(ctx context.Context, bid, walletCode, coinName, address string) error {
+	info, err := c.findAddress(ctx, bid, walletCode, coinName, address)
+	if err != nil {
+		return fmt.Errorf("check address of wallet %s: %w", walletCode, err)
+	}
+	if info == nil {
+		return fmt.Errorf("%w: %s is not a %s address of wallet %s", ErrAddressNotFound, address, coinName, walletCode)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package cactus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTransferClient 创建一个入账钱包cold下有addresses中地址的客户端，地址列表按offset、limit分页，记录请求路径和提交的订单
+func newTransferClient(t *testing.T, addresses []string, paths *[]string, orders *[]model.CreateOrderReq) *ClientImpl {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		*paths = append(*paths, r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/order/create") {
+			var order model.CreateOrderReq
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&order))
+			*orders = append(*orders, order)
+			w.Write([]byte(`{"code":0,"data":{"order_no":"` + order.OrderNo + `"}}`))
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := len(addresses)
+		if limit, _ := strconv.Atoi(r.URL.Query().Get("limit")); limit > 0 {
+			end = min(offset+limit, end)
+		}
+		list := make([]map[string]string, 0, len(addresses))
+		for _, address := range addresses[min(offset, end):end] {
+			list = append(list, map[string]string{"address": address, "wallet_code": "cold"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code": 0,
+			"data": map[string]interface{}{"total": len(addresses), "list": list},
+		})
+	})
+}
+
+// TestTransferBetweenWallets 测试入账地址的选取，地址查询和订单都使用请求中的业务线
+func TestTransferBetweenWallets(t *testing.T) {
+	var paths []string
+	var orders []model.CreateOrderReq
+	client := newTransferClient(t, []string{"cold-1", "cold-2"}, &paths, &orders)
+
+	resp, err := client.TransferBetweenWallets(context.Background(), &model.TransferReq{
+		BID:            "b2",
+		FromWalletCode: "hot",
+		ToWalletCode:   "cold",
+		CoinName:       "ETH",
+		Amount:         decimal.RequireFromString("1.25"),
+		OrderNo:        "t1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "t1", resp.Data.OrderNo)
+	assert.Equal(t, []string{
+		"/custody/v1/api/projects/b2/wallets/cold/addresses",
+		"/custody/v1/api/projects/b2/order/create",
+	}, paths)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "hot", orders[0].FromWalletCode)
+	require.Len(t, orders[0].DestAddressItemList, 1)
+	assert.Equal(t, "cold-1", orders[0].DestAddressItemList[0].DestAddress)
+	assert.Equal(t, "1.25", orders[0].DestAddressItemList[0].Amount.String())
+}
+
+// TestTransferBetweenWalletsToAddress 测试指定的入账地址必须属于入账钱包
+func TestTransferBetweenWalletsToAddress(t *testing.T) {
+	var paths []string
+	var orders []model.CreateOrderReq
+	client := newTransferClient(t, []string{"cold-1", "cold-2"}, &paths, &orders)
+	req := &model.TransferReq{
+		FromWalletCode: "hot",
+		ToWalletCode:   "cold",
+		ToAddress:      "cold-2",
+		CoinName:       "ETH",
+		Amount:         decimal.NewFromInt(1),
+	}
+
+	_, err := client.TransferBetweenWallets(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "cold-2", orders[0].DestAddressItemList[0].DestAddress)
+
+	// 完全一致的地址不在关键字查询的第一页
+	similar := make([]string, 0, 61)
+	for i := 0; i < 60; i++ {
+		similar = append(similar, fmt.Sprintf("cold-2%02d", i))
+	}
+	paged := newTransferClient(t, append(similar, "cold-2"), &paths, &orders)
+	_, err = paged.TransferBetweenWallets(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, "cold-2", orders[1].DestAddressItemList[0].DestAddress)
+
+	req.ToAddress = "external"
+	_, err = client.TransferBetweenWallets(context.Background(), req)
+	assert.ErrorIs(t, err, ErrAddressNotFound)
+	assert.Len(t, orders, 2)
+}
+
+// TestTransferBetweenWalletsErrors 测试同一钱包之间的转账和入账钱包没有地址时被拒绝
+func TestTransferBetweenWalletsErrors(t *testing.T) {
+	var paths []string
+	var orders []model.CreateOrderReq
+	client := newTransferClient(t, nil, &paths, &orders)
+	req := &model.TransferReq{
+		FromWalletCode: "cold",
+		ToWalletCode:   "cold",
+		CoinName:       "ETH",
+		Amount:         decimal.NewFromInt(1),
+	}
+
+	_, err := client.TransferBetweenWallets(context.Background(), req)
+	assert.EqualError(t, err, "from and to wallet must differ")
+	assert.Empty(t, paths)
+
+	req.FromWalletCode = "hot"
+	_, err = client.TransferBetweenWallets(context.Background(), req)
+	assert.EqualError(t, err, "wallet cold has no ETH address")
+	assert.Equal(t, []string{"/custody/v1/api/projects//wallets/cold/addresses"}, paths)
+	assert.Empty(t, orders)
+}
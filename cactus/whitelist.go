@@ -0,0 +1,48 @@
+package cactus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-cactus/model"
+)
+
+// ErrNotWhitelisted 收款地址不在提币白名单内
+var ErrNotWhitelisted = errors.New("destination address is not whitelisted")
+
+// ListAllWhitelist 自动翻页取出某币种的全部白名单地址
+func ListAllWhitelist(ctx context.Context, client Client, req model.ListWhitelistReq) ([]model.WhitelistAddress, error) {
+	return listAll(func(offset, limit int) (*model.ListWhitelistResp, error) {
+		req.Offset, req.Limit = &offset, &limit
+		return client.ListWhitelist(ctx, &req)
+	})
+}
+
+// checkWhitelist 检查订单的每个收款地址都在白名单内，收款项带memo时memo也必须一致
+func (c *ClientImpl) checkWhitelist(ctx context.Context, req *model.CreateOrderReq) error {
+	list, err := ListAllWhitelist(ctx, c, model.ListWhitelistReq{BID: req.BID, CoinName: req.CoinName})
+	if err != nil {
+		return fmt.Errorf("whitelist precheck: %w", err)
+	}
+
+	type entry struct{ address, memo string }
+	allowed := make(map[entry]bool, len(list))
+	for _, w := range list {
+		allowed[entry{w.Address, stringValue(w.Memo)}] = true
+	}
+	for _, item := range req.DestAddressItemList {
+		if !allowed[entry{item.DestAddress, stringValue(item.Memo)}] {
+			return fmt.Errorf("%w: %s %s memo=%q", ErrNotWhitelisted, req.CoinName, item.DestAddress, stringValue(item.Memo))
+		}
+	}
+	return nil
+}
+
+// stringValue 取字符串指针的值，nil时返回空串
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+)
+
+// 私钥密码所在的环境变量，未设置时在终端提示输入
+const (
+	keyPassEnv   = "CACTUS_KEY_PASS"
+	storePassEnv = "CACTUS_STORE_PASS"
+)
+
+const keysUsage = `usage: cactus keys <command> [flags]
+
+commands:
+  generate        create a P-256 key pair, write PEM and optionally PKCS#12
+  export-public   print the public key in the format uploaded to Cactus
+  inspect         show format, curve, public key and fingerprint of a key file
+  convert         convert a key between PEM and PKCS#12 (.p12/.pfx)
+  encrypt         write a passphrase-protected (scrypt + AES-GCM) PEM file
+
+Passwords for PKCS#12 and encrypted PEM files are read from CACTUS_KEY_PASS,
+or prompted for on the terminal when it is not set. CACTUS_STORE_PASS
+defaults to the key password and must equal it for PKCS#12.
+Existing output files are never overwritten unless -force is given.
+`
+
+// runKeys 分发keys子命令
+func runKeys(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keysUsage)
+		return errors.New("missing keys command")
+	}
+	switch args[0] {
+	case "generate":
+		return keysGenerate(args[1:])
+	case "export-public":
+		return keysExportPublic(args[1:])
+	case "inspect":
+		return keysInspect(args[1:])
+	case "convert":
+		return keysConvert(args[1:])
+	case "encrypt":
+		return keysEncrypt(args[1:])
+	default:
+		fmt.Fprint(os.Stderr, keysUsage)
+		return fmt.Errorf("unknown keys command %q", args[0])
+	}
+}
+
+// keyFormat 私钥文件的格式
+type keyFormat int
+
+const (
+	formatPEM keyFormat = iota
+	formatEncryptedPEM
+	formatPKCS12
+)
+
+func (f keyFormat) String() string {
+	switch f {
+	case formatEncryptedPEM:
+		return "encrypted PEM (scrypt, AES-256-GCM)"
+	case formatPKCS12:
+		return "PKCS#12"
+	default:
+		return "PEM"
+	}
+}
+
+// detectFormat 按内容识别私钥格式，不是PEM的按PKCS#12处理，与LoadPrivateKeyFile一致
+func detectFormat(data []byte) keyFormat {
+	block, _ := pem.Decode(data)
+	switch {
+	case block == nil:
+		return formatPKCS12
+	case block.Type == cactus.EncryptedPEMType:
+		return formatEncryptedPEM
+	default:
+		return formatPEM
+	}
+}
+
+// keyPassword 读取已有私钥文件的密码，环境变量未设置时在终端提示输入
+func keyPassword(path string) (string, error) {
+	if pass, ok := os.LookupEnv(keyPassEnv); ok {
+		return pass, nil
+	}
+	pass, err := cactus.PromptPassphrase("password for " + path + ": ")()
+	if err != nil {
+		return "", fmt.Errorf("%s is not set: %w", keyPassEnv, err)
+	}
+	return string(pass), nil
+}
+
+// newKeyPassword 读取新私钥文件的密码，环境变量未设置时在终端提示输入两次
+func newKeyPassword(path string) (string, error) {
+	if pass, ok := os.LookupEnv(keyPassEnv); ok {
+		return pass, nil
+	}
+	pass, err := promptNew("new password for " + path + ": ")
+	if err != nil {
+		return "", fmt.Errorf("%s is not set: %w", keyPassEnv, err)
+	}
+	return string(pass), nil
+}
+
+// promptNew 在终端提示输入两次新口令，两次不一致时报错
+func promptNew(prompt string) ([]byte, error) {
+	pass, err := cactus.PromptPassphrase(prompt)()
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := cactus.PromptPassphrase("repeat " + prompt)()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pass, confirm) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return pass, nil
+}
+
+// loadKey 读取私钥文件并返回按内容识别的格式，PKCS#12和加密PEM需要密码
+func loadKey(path string) (*ecdsa.PrivateKey, keyFormat, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	format := detectFormat(data)
+	var pass string
+	if format != formatPEM {
+		if pass, err = keyPassword(path); err != nil {
+			return nil, 0, err
+		}
+	}
+	key, err := cactus.LoadPrivateKeyFile(path, pass)
+	if err != nil {
+		return nil, 0, err
+	}
+	return key, format, nil
+}
+
+// isPKCS12Path 按扩展名判断输出文件是否为PKCS#12
+func isPKCS12Path(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".p12" || ext == ".pfx"
+}
+
+// checkOutputs 未指定-force时确认输出文件都不存在，在生成密钥和询问密码之前报错
+func checkOutputs(force bool, paths ...string) error {
+	if force {
+		return nil
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists, use -force to overwrite it", path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile 写出私钥文件，未指定force时不覆盖已有文件，避免误删已上传公钥对应的私钥
+func writeFile(path string, data []byte, force bool) error {
+	flag := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flag, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writePKCS12 写出PKCS#12密钥库，密钥库密码未设置时沿用私钥密码
+func writePKCS12(path string, key *ecdsa.PrivateKey, force bool) error {
+	keyPass, err := newKeyPassword(path)
+	if err != nil {
+		return err
+	}
+	data, err := cactus.EncodePKCS12(key, keyPass, os.Getenv(storePassEnv))
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data, force)
+}
+
+// writeKey 按输出文件扩展名写出PEM或PKCS#12私钥
+func writeKey(path string, key *ecdsa.PrivateKey, force bool) error {
+	if isPKCS12Path(path) {
+		return writePKCS12(path, key, force)
+	}
+	data, err := cactus.EncodePEM(key)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, data, force)
+}
+
+// printPublicKey 输出公钥，asPEM为false时输出单行base64
+func printPublicKey(key *ecdsa.PrivateKey, asPEM bool) error {
+	if asPEM {
+		data, err := cactus.PublicKeyPEM(&key.PublicKey)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+	pub, err := cactus.PublicKeyBase64(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	fmt.Println(pub)
+	return nil
+}
+
+// keysGenerate 生成密钥对
+func keysGenerate(args []string) error {
+	fs := flag.NewFlagSet("keys generate", flag.ContinueOnError)
+	out := fs.String("out", "cactus_key.pem", "PEM private key output path")
+	p12 := fs.String("p12", "", "also write a PKCS#12 keystore to this path")
+	force := fs.Bool("force", false, "overwrite existing output files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	outputs := []string{*out}
+	if *p12 != "" {
+		outputs = append(outputs, *p12)
+	}
+	if err := checkOutputs(*force, outputs...); err != nil {
+		return err
+	}
+
+	key, err := cactus.GenerateKey()
+	if err != nil {
+		return err
+	}
+	if err := writeKey(*out, key, *force); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote private key to %s\n", *out)
+	if *p12 != "" {
+		if err := writePKCS12(*p12, key, *force); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "wrote PKCS#12 keystore to %s\n", *p12)
+	}
+	fmt.Fprintln(os.Stderr, "public key to upload to Cactus:")
+	return printPublicKey(key, false)
+}
+
+// keysExportPublic 导出公钥
+func keysExportPublic(args []string) error {
+	fs := flag.NewFlagSet("keys export-public", flag.ContinueOnError)
+	in := fs.String("in", model.SIGN_PIRVATE_PATH, "PEM or PKCS#12 private key path")
+	asPEM := fs.Bool("pem", false, "print a PEM block instead of single-line base64")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, _, err := loadKey(*in)
+	if err != nil {
+		return err
+	}
+	return printPublicKey(key, *asPEM)
+}
+
+// keysInspect 查看密钥信息
+func keysInspect(args []string) error {
+	fs := flag.NewFlagSet("keys inspect", flag.ContinueOnError)
+	in := fs.String("in", model.SIGN_PIRVATE_PATH, "PEM or PKCS#12 private key path")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, format, err := loadKey(*in)
+	if err != nil {
+		return err
+	}
+	pub, err := cactus.PublicKeyBase64(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	fingerprint, err := cactus.PublicKeyFingerprint(&key.PublicKey)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("file:        %s\n", *in)
+	fmt.Printf("format:      %s\n", format)
+	fmt.Printf("curve:       %s\n", key.Curve.Params().Name)
+	fmt.Printf("public key:  %s\n", pub)
+	fmt.Printf("fingerprint: SHA256:%s\n", fingerprint)
+	return nil
+}
+
+// keysConvert 在PEM和PKCS#12之间转换
+func keysConvert(args []string) error {
+	fs := flag.NewFlagSet("keys convert", flag.ContinueOnError)
+	in := fs.String("in", "", "input PEM or PKCS#12 private key path")
+	out := fs.String("out", "", "output path, .p12/.pfx writes PKCS#12, anything else writes PEM")
+	force := fs.Bool("force", false, "overwrite an existing output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" {
+		return errors.New("keys convert requires -in and -out")
+	}
+	if err := checkOutputs(*force, *out); err != nil {
+		return err
+	}
+
+	key, _, err := loadKey(*in)
+	if err != nil {
+		return err
+	}
+	if err := writeKey(*out, key, *force); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *out)
+	return nil
+}
+
+// keysEncrypt 用口令加密私钥，输出可被EncryptedPEMFileSource读取的文件
+func keysEncrypt(args []string) error {
+	fs := flag.NewFlagSet("keys encrypt", flag.ContinueOnError)
+	in := fs.String("in", "", "input PEM or PKCS#12 private key path")
+	out := fs.String("out", "", "encrypted PEM output path")
+	passEnv := fs.String("passphrase-env", "", "read the passphrase from this environment variable instead of prompting")
+	force := fs.Bool("force", false, "overwrite an existing output file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" {
+		return errors.New("keys encrypt requires -in and -out")
+	}
+	if err := checkOutputs(*force, *out); err != nil {
+		return err
+	}
+
+	key, _, err := loadKey(*in)
+	if err != nil {
+		return err
+	}
+	var pass []byte
+	if *passEnv != "" {
+		pass, err = cactus.EnvPassphrase(*passEnv)()
+	} else {
+		pass, err = promptNew("new passphrase: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	pemData, err := cactus.EncodePEM(key)
+	if err != nil {
+		return err
+	}
+	data, err := cactus.EncryptPEM(pemData, pass)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*out, data, *force); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *out)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout 运行fn并返回其写到标准输出的内容
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := fn()
+	os.Stdout = stdout
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, runErr)
+	return string(out)
+}
+
+// TestKeysGenerateNoOverwrite 测试generate默认不覆盖已有私钥，-force时才覆盖
+func TestKeysGenerateNoOverwrite(t *testing.T) {
+	t.Setenv(keyPassEnv, "secret")
+	dir := t.TempDir()
+	pemPath, p12Path := filepath.Join(dir, "key.pem"), filepath.Join(dir, "key.p12")
+
+	pub := captureStdout(t, func() error { return runKeys([]string{"generate", "-out", pemPath, "-p12", p12Path}) })
+	original, err := os.ReadFile(pemPath)
+	require.NoError(t, err)
+	assert.Equal(t, pub, captureStdout(t, func() error { return runKeys([]string{"export-public", "-in", p12Path}) }))
+
+	err = runKeys([]string{"generate", "-out", pemPath})
+	assert.EqualError(t, err, pemPath+" already exists, use -force to overwrite it")
+	err = runKeys([]string{"generate", "-out", filepath.Join(dir, "new.pem"), "-p12", p12Path})
+	assert.EqualError(t, err, p12Path+" already exists, use -force to overwrite it")
+	_, err = os.Stat(filepath.Join(dir, "new.pem"))
+	assert.True(t, os.IsNotExist(err))
+	current, err := os.ReadFile(pemPath)
+	require.NoError(t, err)
+	assert.Equal(t, original, current)
+
+	captureStdout(t, func() error { return runKeys([]string{"generate", "-out", pemPath, "-force"}) })
+	current, err = os.ReadFile(pemPath)
+	require.NoError(t, err)
+	assert.NotEqual(t, original, current)
+}
+
+// TestKeysInspectDetectsFormat 测试inspect按文件内容而不是扩展名识别格式，convert和encrypt输出同一个私钥
+func TestKeysInspectDetectsFormat(t *testing.T) {
+	t.Setenv(keyPassEnv, "secret")
+	t.Setenv("TEST_PASSPHRASE", "passphrase")
+	dir := t.TempDir()
+	pemPath := filepath.Join(dir, "key.pem")
+	pub := strings.TrimSpace(captureStdout(t, func() error { return runKeys([]string{"generate", "-out", pemPath}) }))
+
+	p12Path, encPath := filepath.Join(dir, "key.p12"), filepath.Join(dir, "key.enc.pem")
+	require.NoError(t, runKeys([]string{"convert", "-in", pemPath, "-out", p12Path}))
+	require.NoError(t, runKeys([]string{"encrypt", "-in", p12Path, "-out", encPath, "-passphrase-env", "TEST_PASSPHRASE"}))
+	assert.EqualError(t, runKeys([]string{"convert", "-in", pemPath, "-out", p12Path}),
+		p12Path+" already exists, use -force to overwrite it")
+
+	// 扩展名与内容不符的文件
+	misnamed := filepath.Join(dir, "key.pem.bak")
+	data, err := os.ReadFile(p12Path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(misnamed, data, 0o600))
+
+	for path, format := range map[string]string{
+		pemPath:  "PEM",
+		misnamed: "PKCS#12",
+	} {
+		out := captureStdout(t, func() error { return runKeys([]string{"inspect", "-in", path}) })
+		assert.Contains(t, out, "format:      "+format+"\n", path)
+		assert.Contains(t, out, "public key:  "+pub+"\n", path)
+	}
+
+	t.Setenv(keyPassEnv, "passphrase")
+	out := captureStdout(t, func() error { return runKeys([]string{"inspect", "-in", encPath}) })
+	assert.Contains(t, out, "format:      encrypted PEM (scrypt, AES-256-GCM)\n")
+	assert.Contains(t, out, "public key:  "+pub+"\n")
+}
@@ -0,0 +1,36 @@
+// cactus 是go-cactus的命令行工具
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: cactus <command> [arguments]
+
+commands:
+  keys    generate, inspect and convert API signing keys
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keys":
+		err = runKeys(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cactus:", err)
+		os.Exit(1)
+	}
+}
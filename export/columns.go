@@ -0,0 +1,81 @@
+package export
+
+import (
+	"strconv"
+
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// timeLayout RFC3339，固定保留毫秒
+const timeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// TxDetailColumns 记录明细的列顺序，每个vin/vout单独一行，leg_*列描述该行对应的vin或vout
+var TxDetailColumns = []string{
+	"id", "wallet_code", "wallet_type", "coin_name", "order_no", "tx_id", "tx_type", "tx_status",
+	"block_height", "confirm_ratio", "tx_size", "deposit_amount", "withdraw_amount", "tx_fee",
+	"gas_price", "gas_limit", "miner_reward", "wallet_balance", "remark_detail",
+	"tx_time", "create_time",
+	"leg_type", "leg_index", "leg_address", "leg_tag", "leg_amount", "leg_balance", "leg_is_change", "leg_desc",
+}
+
+// TxSummaryColumns 交易记录概要的列顺序
+var TxSummaryColumns = []string{
+	"wallet_code", "chain", "wallet_type", "coin_name", "order_no", "tx_id", "tx_type",
+	"block_height", "amount", "wallet_balance", "remark_detail", "tx_time", "create_time",
+}
+
+// txDetailRows 把一条记录明细展开成多行，没有vin/vout时只输出一行且leg_*列为空
+func txDetailRows(item model.TxDetailItem) [][]string {
+	base := []string{
+		strconv.Itoa(item.ID), item.WalletCode, item.WalletType, item.CoinName, item.OrderNo,
+		item.TxID, item.TxType, item.TxStatus,
+		strconv.FormatInt(item.BlockHeight, 10), item.ConfirmRatio, strconv.FormatInt(item.TxSize, 10),
+		item.DepositAmount.String(), item.WithdrawAmount.String(), item.TxFee.String(),
+		stringValue(item.GasPrice), stringValue(item.GasLimit), stringValue(item.MinerReward),
+		item.WalletBalance.String(), stringValue(item.RemarkDetail),
+		formatTime(item.TxTimeStamp), formatTime(item.CreateTimeStamp),
+	}
+	leg := func(kind string, index int, address string, tag *string, amount, balance decimal.Decimal, isChange int, desc *string) []string {
+		row := make([]string, 0, len(TxDetailColumns))
+		row = append(row, base...)
+		return append(row, kind, strconv.Itoa(index), address, stringValue(tag), amount.String(),
+			balance.String(), strconv.Itoa(isChange), stringValue(desc))
+	}
+
+	var rows [][]string
+	for _, v := range item.Vins {
+		rows = append(rows, leg("vin", v.Index, v.Address, v.Tag, v.Amount, v.Balance, v.IsChange, v.Desc))
+	}
+	for _, v := range item.Vouts {
+		rows = append(rows, leg("vout", v.Index, v.Address, v.Tag, v.Amount, v.Balance, v.IsChange, v.Desc))
+	}
+	if len(rows) == 0 {
+		rows = append(rows, append(base, make([]string, len(TxDetailColumns)-len(base))...))
+	}
+	return rows
+}
+
+func txSummaryRow(item model.TxSummaryItem) []string {
+	return []string{
+		item.WalletCode, item.Chain, item.WalletType, item.CoinName, item.OrderNo, item.TxID, item.TxType,
+		strconv.FormatInt(item.BlockHeight, 10), item.Amount.String(), item.WalletBalance.String(),
+		item.RemarkDetail, formatTime(item.TxTimeStamp), formatTime(item.CreateTimeStamp),
+	}
+}
+
+// formatTime 时间戳转为UTC的RFC3339，0输出空串
+func formatTime(ts model.Timestamp) string {
+	if ts.IsZero() {
+		return ""
+	}
+	return ts.Time().Format(timeLayout)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,225 @@
+// Package export 以CSV或JSON Lines流式导出钱包交易记录
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+)
+
+// Checkpoint 导出进度，导出中断后可传入Config.Resume继续
+type Checkpoint struct {
+	WalletCode  string          `json:"wallet_code"`             // 正在导出的钱包
+	LastID      int             `json:"last_id,omitempty"`       // 明细：最后写出的记录ID
+	LastTime    model.Timestamp `json:"last_time,omitempty"`     // 最后写出记录的创建时间
+	LastTxID    string          `json:"last_tx_id,omitempty"`    // 概要：最后写出记录的交易哈希
+	LastOrderNo string          `json:"last_order_no,omitempty"` // 概要：最后写出记录的订单号
+	Rows        int             `json:"rows"`                    // 累计写出的行数
+}
+
+// Config 导出配置
+type Config struct {
+	BID          string           // 业务线ID，为空时使用客户端环境的业务线
+	WalletCodes  []string         // 需要导出的钱包，为空时使用客户端Profile中的钱包
+	CoinName     string           // 币种名称，概要导出必填
+	TxTypes      []string         // 记录类型
+	Start        time.Time        // 时间窗口起点（含），零值表示不限
+	End          time.Time        // 时间窗口终点（不含），零值表示不限
+	Format       Format           // 导出格式，默认CSV
+	Resume       *Checkpoint      // 从上次的进度继续，此时不再写表头
+	OnCheckpoint func(Checkpoint) // 每条记录写出并刷新后回调，用于持久化进度
+}
+
+// Exporter 交易记录导出器
+type Exporter struct {
+	client cactus.Client
+	cfg    Config
+}
+
+// New 创建导出器，没有可导出的钱包时返回cactus.ErrNoWallets，避免只写出表头却报告成功
+func New(client cactus.Client, cfg Config) (*Exporter, error) {
+	if len(cfg.WalletCodes) == 0 {
+		cfg.WalletCodes = client.WalletCodes()
+	}
+	if len(cfg.WalletCodes) == 0 {
+		return nil, cactus.ErrNoWallets
+	}
+	return &Exporter{client: client, cfg: cfg}, nil
+}
+
+// TxDetails 导出记录明细，vin/vout展开为多行，返回最后的进度
+func (e *Exporter) TxDetails(ctx context.Context, w io.Writer) (Checkpoint, error) {
+	out, cp, err := e.begin(w, TxDetailColumns)
+	if err != nil {
+		return cp, err
+	}
+	for _, walletCode := range e.wallets() {
+		resume := e.resumeFor(walletCode)
+		req := model.TxDetailReq{
+			BID:        e.cfg.BID,
+			WalletCode: walletCode,
+			CoinName:   e.cfg.CoinName,
+			TxTypes:    e.cfg.TxTypes,
+		}
+		req.WithTimeRange(e.window(resume))
+		err := cactus.EachTxDetail(ctx, e.client, req, func(item model.TxDetailItem) error {
+			if resume != nil && (item.CreateTimeStamp < resume.LastTime ||
+				item.CreateTimeStamp == resume.LastTime && item.ID <= resume.LastID) {
+				return nil
+			}
+			rows := txDetailRows(item)
+			for _, row := range rows {
+				if err := out.Write(row); err != nil {
+					return err
+				}
+			}
+			cp = Checkpoint{WalletCode: walletCode, LastID: item.ID, LastTime: item.CreateTimeStamp, Rows: cp.Rows + len(rows)}
+			return e.commit(out, cp)
+		})
+		if err != nil {
+			return cp, err
+		}
+	}
+	return cp, out.Flush()
+}
+
+// TxSummaries 导出交易记录概要，返回最后的进度
+func (e *Exporter) TxSummaries(ctx context.Context, w io.Writer) (Checkpoint, error) {
+	out, cp, err := e.begin(w, TxSummaryColumns)
+	if err != nil {
+		return cp, err
+	}
+	write := func(walletCode string, items []model.TxSummaryItem) error {
+		for _, item := range items {
+			if err := out.Write(txSummaryRow(item)); err != nil {
+				return err
+			}
+			cp = Checkpoint{
+				WalletCode:  walletCode,
+				LastTime:    item.CreateTimeStamp,
+				LastTxID:    item.TxID,
+				LastOrderNo: item.OrderNo,
+				Rows:        cp.Rows + 1,
+			}
+			if err := e.commit(out, cp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, walletCode := range e.wallets() {
+		resume := &summaryResume{cp: e.resumeFor(walletCode)}
+		req := model.TxSummaryReq{
+			BID:        e.cfg.BID,
+			WalletCode: walletCode,
+			CoinName:   e.cfg.CoinName,
+			TxTypes:    e.cfg.TxTypes,
+		}
+		req.WithTimeRange(e.window(resume.cp))
+		err := cactus.EachTxSummary(ctx, e.client, req, func(item model.TxSummaryItem) error {
+			return write(walletCode, resume.filter(item))
+		})
+		if err == nil {
+			err = write(walletCode, resume.rest())
+		}
+		if err != nil {
+			return cp, err
+		}
+	}
+	return cp, out.Flush()
+}
+
+// begin 创建写出器，非续传时写表头
+func (e *Exporter) begin(w io.Writer, columns []string) (rowWriter, Checkpoint, error) {
+	var cp Checkpoint
+	out, err := newRowWriter(e.cfg.Format, w, columns)
+	if err != nil {
+		return nil, cp, err
+	}
+	if e.cfg.Resume != nil {
+		return out, *e.cfg.Resume, nil
+	}
+	return out, cp, out.Header()
+}
+
+// commit 刷新已写出的行后再上报进度，保证进度不超前于输出
+func (e *Exporter) commit(out rowWriter, cp Checkpoint) error {
+	if e.cfg.OnCheckpoint == nil {
+		return nil
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	e.cfg.OnCheckpoint(cp)
+	return nil
+}
+
+// wallets 续传时跳过进度之前的钱包
+func (e *Exporter) wallets() []string {
+	if e.cfg.Resume == nil {
+		return e.cfg.WalletCodes
+	}
+	for i, code := range e.cfg.WalletCodes {
+		if code == e.cfg.Resume.WalletCode {
+			return e.cfg.WalletCodes[i:]
+		}
+	}
+	return e.cfg.WalletCodes
+}
+
+// resumeFor 返回钱包对应的续传进度
+func (e *Exporter) resumeFor(walletCode string) *Checkpoint {
+	if e.cfg.Resume != nil && e.cfg.Resume.WalletCode == walletCode {
+		return e.cfg.Resume
+	}
+	return nil
+}
+
+// window 计算查询的时间窗口，续传时从进度的时间开始
+func (e *Exporter) window(resume *Checkpoint) (start, end time.Time) {
+	start, end = e.cfg.Start, e.cfg.End
+	if resume != nil && resume.LastTime.Time().After(start) {
+		start = resume.LastTime.Time()
+	}
+	return start, end
+}
+
+// summaryResume 概要记录没有ID：早于进度时间的记录直接跳过，与进度时间相同的记录跳到上次写出的那条为止；
+// 找不到上次写出的记录时，同一时间的记录全部重新导出
+type summaryResume struct {
+	cp      *Checkpoint
+	done    bool
+	pending []model.TxSummaryItem
+}
+
+func (r *summaryResume) filter(item model.TxSummaryItem) []model.TxSummaryItem {
+	if r.cp == nil || r.done {
+		return []model.TxSummaryItem{item}
+	}
+	switch {
+	case item.CreateTimeStamp < r.cp.LastTime:
+		return nil
+	case item.CreateTimeStamp == r.cp.LastTime:
+		if item.TxID == r.cp.LastTxID && item.OrderNo == r.cp.LastOrderNo {
+			r.done, r.pending = true, nil
+		} else {
+			r.pending = append(r.pending, item)
+		}
+		return nil
+	}
+	r.done = true
+	items := append(r.pending, item)
+	r.pending = nil
+	return items
+}
+
+// rest 返回遍历结束时仍未确定的记录
+func (r *summaryResume) rest() []model.TxSummaryItem {
+	if r.done {
+		return nil
+	}
+	return r.pending
+}
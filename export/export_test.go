@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go-cactus/cactus"
+	"go-cactus/internal/cactustest"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExporter 创建导出器，配置有误时结束测试
+func newExporter(t *testing.T, client cactus.Client, cfg Config) *Exporter {
+	t.Helper()
+	e, err := New(client, cfg)
+	require.NoError(t, err)
+	return e
+}
+
+// TestTxDetailsCSV 测试vin/vout展开、金额与时间格式以及续传
+func TestTxDetailsCSV(t *testing.T) {
+	client := &cactustest.Client{TxDetails: []model.TxDetailItem{
+		{ID: 1, CoinName: "BTC", TxID: "t1", DepositAmount: decimal.RequireFromString("0.10000000"),
+			TxTimeStamp: 1735689600123, CreateTimeStamp: 1735689600123,
+			Vins:  []model.Vin{{Address: "a", Amount: decimal.RequireFromString("0.2")}},
+			Vouts: []model.Vout{{Address: "b", Index: 1, Amount: decimal.RequireFromString("0.1")}}},
+		{ID: 2, CoinName: "BTC", TxID: "t2", WithdrawAmount: decimal.RequireFromString("1"), WalletBalance: decimal.RequireFromString("0.300000000000000001"), CreateTimeStamp: 1735689700000},
+	}}
+
+	var buf bytes.Buffer
+	var checkpoints []Checkpoint
+	cp, err := newExporter(t, client, Config{
+		WalletCodes:  []string{"w1"},
+		OnCheckpoint: func(cp Checkpoint) { checkpoints = append(checkpoints, cp) },
+	}).TxDetails(context.Background(), &buf)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, strings.Join(TxDetailColumns, ","), lines[0])
+	assert.Contains(t, lines[1], ",0.1,")
+	assert.Contains(t, lines[1], "2025-01-01T00:00:00.123Z")
+	assert.Contains(t, lines[1], ",vin,0,a,,0.2,")
+	assert.Contains(t, lines[2], ",vout,1,b,,0.1,")
+	assert.Contains(t, lines[3], ",0.300000000000000001,")
+	assert.Equal(t, Checkpoint{WalletCode: "w1", LastID: 2, LastTime: 1735689700000, Rows: 3}, cp)
+	require.Len(t, checkpoints, 2)
+
+	// 从第一条之后续传，不再写表头
+	buf.Reset()
+	_, err = newExporter(t, client, Config{WalletCodes: []string{"w1"}, Resume: &checkpoints[0]}).TxDetails(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	assert.True(t, strings.HasPrefix(buf.String(), "2,"))
+}
+
+// TestTxSummariesJSONL 测试JSONL字段顺序与同一时间记录的续传
+func TestTxSummariesJSONL(t *testing.T) {
+	client := &cactustest.Client{TxSummaries: []model.TxSummaryItem{
+		{TxID: "t1", Amount: decimal.RequireFromString("1.5"), CreateTimeStamp: 1000},
+		{TxID: "t2", Amount: decimal.NewFromInt(2), CreateTimeStamp: 1000},
+		{TxID: "t3", Amount: decimal.NewFromInt(3), CreateTimeStamp: 2000},
+	}}
+
+	var buf bytes.Buffer
+	_, err := newExporter(t, client, Config{
+		WalletCodes: []string{"w1"},
+		Format:      JSONL,
+		Resume:      &Checkpoint{WalletCode: "w1", LastTime: 1000, LastTxID: "t1", Rows: 1},
+	}).TxSummaries(context.Background(), &buf)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], `{"wallet_code":"","chain":"",`))
+	assert.Contains(t, lines[0], `"tx_id":"t2"`)
+	assert.Contains(t, lines[0], `"amount":"2"`)
+	assert.Contains(t, lines[1], `"create_time":"1970-01-01T00:00:02.000Z"`)
+}
+
+// TestNewNoWallets 测试没有指定钱包、客户端也没有配置钱包时拒绝创建导出器
+func TestNewNoWallets(t *testing.T) {
+	_, err := New(&cactustest.Client{}, Config{})
+	assert.ErrorIs(t, err, cactus.ErrNoWallets)
+
+	e, err := New(&cactustest.Client{WalletList: []string{"w1"}}, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"w1"}, e.cfg.WalletCodes)
+}
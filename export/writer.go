@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	CSV   Format = "csv"   // 带表头的CSV
+	JSONL Format = "jsonl" // 每行一个JSON对象
+)
+
+// rowWriter 按固定列顺序写出一行
+type rowWriter interface {
+	Header() error
+	Write(row []string) error
+	Flush() error
+}
+
+func newRowWriter(format Format, w io.Writer, columns []string) (rowWriter, error) {
+	switch format {
+	case CSV, "":
+		return &csvWriter{w: csv.NewWriter(w), columns: columns}, nil
+	case JSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w), columns: columns}, nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (c *csvWriter) Header() error {
+	return c.w.Write(c.columns)
+}
+
+func (c *csvWriter) Write(row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonlWriter 手动拼接对象，保证字段顺序与列顺序一致
+type jsonlWriter struct {
+	w       *bufio.Writer
+	columns []string
+}
+
+func (j *jsonlWriter) Header() error {
+	return nil
+}
+
+func (j *jsonlWriter) Write(row []string) error {
+	j.w.WriteByte('{')
+	for i, col := range j.columns {
+		if i > 0 {
+			j.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(col)
+		value, _ := json.Marshal(row[i])
+		j.w.Write(key)
+		j.w.WriteByte(':')
+		j.w.Write(value)
+	}
+	j.w.WriteByte('}')
+	return j.w.WriteByte('\n')
+}
+
+func (j *jsonlWriter) Flush() error {
+	return j.w.Flush()
+}
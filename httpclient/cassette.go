@@ -0,0 +1,298 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction 回放模式下没有与请求匹配的录制记录
+var ErrNoInteraction = errors.New("cassette: no recorded interaction matches request")
+
+// redactedValue 脱敏后的头取值
+const redactedValue = "REDACTED"
+
+// defaultRedactedHeaders 默认脱敏的请求头和响应头，包含密钥、签名和会话cookie
+var defaultRedactedHeaders = []string{"Authorization", "X-Api-Key", "X-Api-Nonce", "Cookie", "Set-Cookie"}
+
+// RecordedRequest 录制的请求
+type RecordedRequest struct {
+	Method     string      `json:"method"`
+	URI        string      `json:"uri"`         // 规范化后的URI，用于匹配
+	BodySHA256 string      `json:"body_sha256"` // 请求体哈希，用于匹配
+	Body       string      `json:"body,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+}
+
+// RecordedResponse 录制的响应
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Interaction 一次请求与响应
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Cassette 录制/回放用的磁带文件，按方法、规范化URI和请求体哈希匹配请求。
+// 录制的记录保存在内存中，Close时一次性写入文件
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	dirty        bool // 有尚未写入文件的录制记录
+	interactions []Interaction
+	used         []bool // 回放时已被使用过的记录
+	formatURI    func(uri string) (string, error)
+	redact       []string
+}
+
+// CassetteOption 定义磁带的可选配置
+type CassetteOption func(*Cassette)
+
+// WithURIFormatter 设置URI规范化函数，录制和回放都用它生成匹配键
+func WithURIFormatter(fn func(uri string) (string, error)) CassetteOption {
+	return func(c *Cassette) {
+		c.formatURI = fn
+	}
+}
+
+// WithRedactedHeaders 追加需要脱敏的请求头和响应头
+func WithRedactedHeaders(headers ...string) CassetteOption {
+	return func(c *Cassette) {
+		c.redact = append(c.redact, headers...)
+	}
+}
+
+// NewCassette 创建一个空磁带，录制的记录在Close时写入path
+func NewCassette(path string, opts ...CassetteOption) *Cassette {
+	c := &Cassette{
+		path:      path,
+		formatURI: func(uri string) (string, error) { return uri, nil },
+		redact:    append([]string(nil), defaultRedactedHeaders...),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// LoadCassette 从path读取已录制的磁带
+func LoadCassette(path string, opts ...CassetteOption) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	c := NewCassette(path, opts...)
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+	return c, nil
+}
+
+// Interactions 返回已录制的记录
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Close 把录制的记录写入文件，没有新记录时不写
+func (c *Cassette) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	c.dirty = false
+	return nil
+}
+
+// redactHeader 复制头并把需要脱敏的字段替换为redactedValue
+func (c *Cassette) redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range c.redact {
+		if h.Get(name) != "" {
+			h.Set(name, redactedValue)
+		}
+	}
+	return h
+}
+
+// matchKey 生成请求的匹配键
+func (c *Cassette) matchKey(req *http.Request, body []byte) (RecordedRequest, error) {
+	uri, err := c.formatURI(req.URL.RequestURI())
+	if err != nil {
+		return RecordedRequest{}, fmt.Errorf("cassette: failed to format uri: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return RecordedRequest{
+		Method:     req.Method,
+		URI:        uri,
+		BodySHA256: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// record 追加一条脱敏后的记录
+func (c *Cassette) record(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) error {
+	key, err := c.matchKey(req, reqBody)
+	if err != nil {
+		return err
+	}
+	key.Body = string(reqBody)
+	key.Header = c.redactHeader(req.Header)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, Interaction{
+		Request: key,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     c.redactHeader(resp.Header),
+			Body:       string(respBody),
+		},
+	})
+	c.used = append(c.used, false)
+	c.dirty = true
+	return nil
+}
+
+// lookup 按顺序找出第一条未使用的匹配记录
+func (c *Cassette) lookup(req *http.Request, body []byte) (*Interaction, error) {
+	key, err := c.matchKey(req, body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.interactions {
+		r := c.interactions[i].Request
+		if c.used[i] || r.Method != key.Method || r.URI != key.URI || r.BodySHA256 != key.BodySHA256 {
+			continue
+		}
+		c.used[i] = true
+		return &c.interactions[i], nil
+	}
+	return nil, fmt.Errorf("%w: %s %s body_sha256=%s", ErrNoInteraction, key.Method, key.URI, key.BodySHA256)
+}
+
+// readRequestBody 读取请求体，RoundTripper不能修改req，因此优先通过GetBody读取副本。
+// 没有GetBody时读完并关闭原请求体，返回携带请求体副本的克隆用于继续发送
+func readRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		return body, req, err
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	return body, out, nil
+}
+
+// recordingTransport 透传请求并把请求/响应录入磁带
+type recordingTransport struct {
+	next     http.RoundTripper
+	cassette *Cassette
+}
+
+// RoundTrip 实现http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, out, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	closeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err := t.cassette.record(req, reqBody, resp, respBody); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replayTransport 只从磁带回放，不访问网络
+type replayTransport struct {
+	cassette *Cassette
+}
+
+// RoundTrip 实现http.RoundTripper
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, _, err := readRequestBody(req)
+	if req.Body != nil {
+		_ = req.Body.Close() // 不访问网络，由这里关闭请求体
+	}
+	if err != nil {
+		return nil, err
+	}
+	in, err := t.cassette.lookup(req, body)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// WithRecorder 录制模式：正常发送请求，同时把脱敏后的请求和响应录入磁带，调用Cassette.Close写入文件
+func WithRecorder(c *Cassette) Option {
+	return func(hc *HTTPClient) {
+		next := hc.client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		hc.client.Transport = &recordingTransport{next: next, cassette: c}
+	}
+}
+
+// WithReplay 回放模式：只从磁带返回响应，找不到匹配记录时直接报错且不重试
+func WithReplay(c *Cassette) Option {
+	return func(hc *HTTPClient) {
+		hc.client.Transport = &replayTransport{cassette: c}
+	}
+}
@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCassetteRecordAndReplay 测试录制后回放，以及未匹配请求直接失败
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		json.NewEncoder(w).Encode(map[string]string{"message": "recorded"})
+	}))
+
+	recording := NewCassette(path)
+	recorder := NewHTTPClient(WithRecorder(recording))
+	resp, err := recorder.Post(context.Background(), server.URL+"/order?b=2&a=1", map[string]string{"k": "v"},
+		map[string]string{"Authorization": "api ak:secret-signature"})
+	require.NoError(t, err)
+	assert.Equal(t, "session=secret-cookie", resp.Header.Get("Set-Cookie"))
+	resp.Body.Close()
+	server.Close()
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "cassette is written on Close")
+	require.NoError(t, recording.Close())
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret-signature")
+	assert.NotContains(t, string(raw), "secret-cookie")
+	assert.Contains(t, string(raw), redactedValue)
+
+	cassette, err := LoadCassette(path)
+	require.NoError(t, err)
+	replayer := NewHTTPClient(WithReplay(cassette), WithMaxRetries(3))
+
+	resp, err = replayer.Post(context.Background(), server.URL+"/order?b=2&a=1", map[string]string{"k": "v"}, nil)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.JSONEq(t, `{"message":"recorded"}`, string(body))
+
+	// 请求体不同，无法匹配
+	_, err = replayer.Post(context.Background(), server.URL+"/order?b=2&a=1", map[string]string{"k": "other"}, nil)
+	assert.ErrorIs(t, err, ErrNoInteraction)
+}
+
+// TestCassetteKeepsRequestBody 测试录制和回放都不修改传入RoundTrip的请求
+func TestCassetteKeepsRequestBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	cassette := NewCassette(filepath.Join(t.TempDir(), "cassette.json"))
+	for _, rt := range []http.RoundTripper{
+		&recordingTransport{next: http.DefaultTransport, cassette: cassette},
+		&replayTransport{cassette: cassette},
+	} {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/echo", strings.NewReader("payload"))
+		require.NoError(t, err)
+		body := req.Body
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		got, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "payload", string(got))
+		assert.True(t, body == req.Body, "request body must not be replaced")
+	}
+}
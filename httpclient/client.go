@@ -5,21 +5,38 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"time"
 
+	"go-cactus/metrics"
+
 	"github.com/cenkalti/backoff/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// tracerName 本包创建span时使用的instrumentation名称
+const tracerName = "go-cactus/httpclient"
+
 // HTTPClient 封装了带有重试功能的HTTP客户端
 type HTTPClient struct {
 	client      *http.Client
 	maxRetries  int
 	maxWaitTime time.Duration
 	headers     map[string]string // 默认请求头
+
+	tracer     trace.Tracer                  // 每次请求尝试的span
+	propagator propagation.TextMapPropagator // 链路上下文注入请求头
+	metrics    *metrics.ClientMetrics        // 监控指标，为nil时不采集
+	limiter    *rateLimiter                  // 限流器，为nil时不限流
+	breaker    *circuitBreaker               // 熔断器，为nil时不熔断
 }
 
 // Option 定义HTTP客户端的可选配置
@@ -69,6 +86,28 @@ func WithInsecureSkipVerify(skip bool) Option {
 	}
 }
 
+// WithTracerProvider 设置链路追踪，每次请求尝试都会创建一个子span
+// 并按W3C Trace Context规范注入traceparent请求头
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *HTTPClient) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithPropagator 设置链路上下文的传播方式，默认使用W3C Trace Context
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *HTTPClient) {
+		c.propagator = p
+	}
+}
+
+// WithMetrics 设置监控指标，记录每次请求尝试的状态码和重试次数
+func WithMetrics(m *metrics.ClientMetrics) Option {
+	return func(c *HTTPClient) {
+		c.metrics = m
+	}
+}
+
 // NewHTTPClient 创建一个新的HTTP客户端实例
 func NewHTTPClient(opts ...Option) *HTTPClient {
 	transport := &http.Transport{
@@ -82,6 +121,8 @@ func NewHTTPClient(opts ...Option) *HTTPClient {
 		maxRetries:  3,
 		maxWaitTime: 1 * time.Minute,
 		headers:     make(map[string]string),
+		tracer:      noop.NewTracerProvider().Tracer(tracerName),
+		propagator:  propagation.TraceContext{},
 	}
 
 	for _, opt := range opts {
@@ -114,6 +155,7 @@ func closeBody(resp *http.Response) {
 func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	var resp *http.Response
 	var err error
+	attempt := 0
 
 	// 创建重试策略
 	expBackoff := backoff.NewExponentialBackOff()
@@ -125,16 +167,47 @@ func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 			closeBody(resp) // 关闭之前的响应体
 		}
 
-		resp, err = c.client.Do(req)
+		host := req.URL.Host
+		if err := c.limiter.wait(ctx, host, c.metrics); err != nil {
+			return backoff.Permanent(err)
+		}
+		if !c.breaker.allow(host, time.Now(), c.metrics) {
+			return backoff.Permanent(fmt.Errorf("%w: %s", ErrCircuitOpen, host))
+		}
+		// allow之后的每条返回路径都要记录结果或交还试探名额，否则熔断器停在半开状态
+
+		n := attempt
+		attempt++
+		attemptReq, span, cloneErr := c.startAttempt(ctx, req, n)
+		if cloneErr != nil {
+			endAttempt(span, nil, cloneErr)
+			c.breaker.release(host, c.metrics)
+			return backoff.Permanent(cloneErr)
+		}
+
+		resp, err = c.client.Do(attemptReq)
 		if err != nil {
+			c.metrics.ObserveAttempt(req.Method, n, 0)
+			endAttempt(span, nil, err)
+			if errors.Is(err, ErrNoInteraction) {
+				c.breaker.release(host, c.metrics)
+				return backoff.Permanent(err) // 回放缺失记录时重试没有意义
+			}
+			c.breaker.record(host, false, time.Now(), c.metrics)
 			return err
 		}
+		c.metrics.ObserveAttempt(req.Method, n, resp.StatusCode)
 
 		// 如果响应状态码大于等于500，标记为需要重试
 		if resp.StatusCode >= http.StatusInternalServerError {
-			return fmt.Errorf("server error: status code %d", resp.StatusCode)
+			err = fmt.Errorf("server error: status code %d", resp.StatusCode)
+			endAttempt(span, resp, err)
+			c.breaker.record(host, false, time.Now(), c.metrics)
+			return err
 		}
 
+		endAttempt(span, resp, nil)
+		c.breaker.record(host, true, time.Now(), c.metrics)
 		return nil
 	}
 
@@ -147,6 +220,42 @@ func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 	return resp, nil
 }
 
+// startAttempt 为一次请求尝试创建子span，并复制出携带链路上下文的请求
+// 重试时通过GetBody重新获取请求体，避免第二次尝试发送空body
+func (c *HTTPClient) startAttempt(ctx context.Context, req *http.Request, attempt int) (*http.Request, trace.Span, error) {
+	ctx, span := c.tracer.Start(ctx, "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.Int("http.request.resend_count", attempt),
+		),
+	)
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, span, err
+		}
+		attemptReq.Body = body
+	}
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(attemptReq.Header))
+	return attemptReq, span, nil
+}
+
+// endAttempt 记录一次请求尝试的结果并结束span
+func endAttempt(span trace.Span, resp *http.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // DoJSON 执行HTTP请求并解析JSON响应
 func (c *HTTPClient) DoJSON(ctx context.Context, req *http.Request, v interface{}) error {
 	resp, err := c.Do(ctx, req)
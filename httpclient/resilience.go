@@ -0,0 +1,171 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go-cactus/metrics"
+)
+
+// ErrCircuitOpen 熔断器打开期间请求被直接拒绝
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// WithRateLimit 按目标主机限流：每秒最多rps次请求尝试（重试也计入），允许burst次突发。
+// 每次放行前的等待时长上报到限流器指标，rps<=0表示不限流
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *HTTPClient) {
+		if rps <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = &rateLimiter{
+			interval: time.Duration(float64(time.Second) / rps),
+			burst:    max(burst, 1),
+			next:     make(map[string]time.Time),
+		}
+	}
+}
+
+// WithCircuitBreaker 按目标主机熔断：连续failures次尝试失败（传输错误或5xx）后打开并拒绝请求，
+// cooldown之后放行一次试探，成功则关闭、失败则重新打开。状态切换上报到熔断器指标，failures<=0表示不熔断
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *HTTPClient) {
+		if failures <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &circuitBreaker{
+			failures: failures,
+			cooldown: cooldown,
+			hosts:    make(map[string]*circuit),
+		}
+	}
+}
+
+// rateLimiter 按主机的GCRA限流器，为nil时不限流
+type rateLimiter struct {
+	interval time.Duration // 两次放行之间的平均间隔
+	burst    int           // 允许的突发次数
+
+	mu   sync.Mutex
+	next map[string]time.Time // 每个主机下一次请求的理论到达时间
+}
+
+// reserve 预约一次放行，返回需要等待的时长
+func (l *rateLimiter) reserve(host string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tat := l.next[host]
+	if tat.Before(now) {
+		tat = now
+	}
+	wait := tat.Sub(now) - time.Duration(l.burst-1)*l.interval
+	l.next[host] = tat.Add(l.interval)
+	return max(wait, 0)
+}
+
+// wait 等待限流器放行并上报等待时长，ctx结束时返回其错误
+func (l *rateLimiter) wait(ctx context.Context, host string, m *metrics.ClientMetrics) error {
+	if l == nil {
+		return nil
+	}
+	d := l.reserve(host, time.Now())
+	m.SetRateLimiterWait(host, d)
+	if d == 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// circuitBreaker 按主机的熔断器，为nil时不熔断
+type circuitBreaker struct {
+	failures int           // 打开前允许的连续失败次数
+	cooldown time.Duration // 打开后多久放行试探请求
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+// circuit 单个主机的熔断状态
+type circuit struct {
+	state    metrics.CircuitState
+	failures int       // 连续失败次数
+	openedAt time.Time // 最近一次打开的时间
+}
+
+// allow 判断是否放行一次尝试，冷却结束后只放行一次试探
+func (b *circuitBreaker) allow(host string, now time.Time, m *metrics.ClientMetrics) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(host)
+	switch c.state {
+	case metrics.CircuitOpen:
+		if now.Sub(c.openedAt) < b.cooldown {
+			return false
+		}
+		c.state = metrics.CircuitHalfOpen
+		m.SetCircuitState(host, c.state)
+		return true
+	case metrics.CircuitHalfOpen:
+		return false // 试探请求尚未返回
+	}
+	return true
+}
+
+// record 记录一次尝试的结果并切换状态
+func (b *circuitBreaker) record(host string, ok bool, now time.Time, m *metrics.ClientMetrics) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(host)
+	if ok {
+		c.failures = 0
+		if c.state != metrics.CircuitClosed {
+			c.state = metrics.CircuitClosed
+			m.SetCircuitState(host, c.state)
+		}
+		return
+	}
+	c.failures++
+	if c.state == metrics.CircuitHalfOpen || c.failures >= b.failures {
+		c.state, c.openedAt = metrics.CircuitOpen, now
+		m.SetCircuitState(host, c.state)
+	}
+}
+
+// release 放弃一次已放行但没有结果的尝试（如请求未发出），试探请求的名额交还给下一次尝试
+func (b *circuitBreaker) release(host string, m *metrics.ClientMetrics) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuit(host)
+	if c.state == metrics.CircuitHalfOpen {
+		c.state = metrics.CircuitOpen // openedAt不变，冷却已结束，下一次allow重新放行试探
+		m.SetCircuitState(host, c.state)
+	}
+}
+
+func (b *circuitBreaker) circuit(host string) *circuit {
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{}
+		b.hosts[host] = c
+	}
+	return c
+}
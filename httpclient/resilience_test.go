@@ -0,0 +1,129 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-cactus/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsText 以Prometheus文本格式输出注册表
+func metricsText(t *testing.T, reg *metrics.MemoryRegistry) string {
+	var out strings.Builder
+	require.NoError(t, reg.WriteText(&out))
+	return out.String()
+}
+
+// TestCircuitBreaker 测试连续失败后熔断、冷却后试探恢复，并上报熔断器状态
+func TestCircuitBreaker(t *testing.T) {
+	status, requests := http.StatusInternalServerError, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	reg := metrics.NewRegistry()
+	client := NewHTTPClient(
+		WithMaxRetries(0),
+		WithCircuitBreaker(2, 50*time.Millisecond),
+		WithMetrics(metrics.NewClientMetrics(reg)),
+	)
+	get := func() error {
+		resp, err := client.Get(context.Background(), server.URL, nil, nil)
+		closeBody(resp)
+		return err
+	}
+
+	assert.Error(t, get())
+	assert.Error(t, get())
+	assert.ErrorIs(t, get(), ErrCircuitOpen)
+	assert.Equal(t, 2, requests)
+	assert.Contains(t, metricsText(t, reg), `cactus_circuit_breaker_state{name="`+u.Host+`"} 2`)
+
+	time.Sleep(60 * time.Millisecond)
+	status = http.StatusOK
+	assert.NoError(t, get())
+	assert.NoError(t, get())
+	assert.Equal(t, 4, requests)
+	assert.Contains(t, metricsText(t, reg), `cactus_circuit_breaker_state{name="`+u.Host+`"} 0`)
+}
+
+// TestCircuitBreakerHalfOpenFailure 测试试探请求失败时立即重新熔断
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	breaker := &circuitBreaker{failures: 3, cooldown: time.Minute, hosts: make(map[string]*circuit)}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		require.True(t, breaker.allow("h", now, nil))
+		breaker.record("h", false, now, nil)
+	}
+	assert.False(t, breaker.allow("h", now.Add(time.Second), nil))
+
+	later := now.Add(time.Minute)
+	assert.True(t, breaker.allow("h", later, nil))
+	assert.False(t, breaker.allow("h", later, nil)) // 同一时间只放行一次试探
+	breaker.record("h", false, later, nil)
+	assert.False(t, breaker.allow("h", later.Add(time.Second), nil))
+	assert.True(t, breaker.allow("other", later, nil))
+}
+
+// TestCircuitBreakerRelease 测试试探请求没有发出时交还名额，熔断器不会停在半开状态
+func TestCircuitBreakerRelease(t *testing.T) {
+	breaker := &circuitBreaker{failures: 1, cooldown: time.Minute, hosts: make(map[string]*circuit)}
+	now := time.Now()
+	require.True(t, breaker.allow("h", now, nil))
+	breaker.record("h", false, now, nil)
+
+	later := now.Add(time.Minute)
+	require.True(t, breaker.allow("h", later, nil))
+	breaker.release("h", nil)
+	require.True(t, breaker.allow("h", later, nil))
+	breaker.record("h", true, later, nil)
+	assert.True(t, breaker.allow("h", later, nil))
+}
+
+// TestCircuitBreakerReplayMiss 测试半开时回放缺失记录不会让熔断器一直拒绝请求
+func TestCircuitBreakerReplayMiss(t *testing.T) {
+	client := NewHTTPClient(WithMaxRetries(0), WithCircuitBreaker(1, time.Millisecond), WithReplay(NewCassette("")))
+	client.breaker.hosts["example.com"] = &circuit{state: metrics.CircuitOpen, openedAt: time.Now().Add(-time.Second)}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Get(context.Background(), "https://example.com/a", nil, nil)
+		assert.ErrorIs(t, err, ErrNoInteraction)
+	}
+}
+
+// TestRateLimit 测试超过突发次数的请求等待放行，并上报等待时长
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	reg := metrics.NewRegistry()
+	client := NewHTTPClient(WithRateLimit(20, 2), WithMetrics(metrics.NewClientMetrics(reg)))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(context.Background(), server.URL, nil, nil)
+		require.NoError(t, err)
+		closeBody(resp)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	assert.Contains(t, metricsText(t, reg), `cactus_rate_limiter_wait_seconds{name="`+u.Host+`"} 0.0`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Get(ctx, server.URL, nil, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
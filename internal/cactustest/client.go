@@ -0,0 +1,184 @@
+// Package cactustest 提供测试用的内存版cactus.Client
+package cactustest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+)
+
+// ErrNotStubbed 调用了Client没有模拟的方法
+var ErrNotStubbed = errors.New("cactustest: method not stubbed")
+
+// Client 按字段中的数据响应的cactus.Client，没有模拟的方法返回ErrNotStubbed。
+// 列表接口按Offset、Limit分页，地址列表按KeyWord和MinBalance（按Coins中的精度换算）过滤，
+// 记录查询按创建时间过滤，与服务端一致
+type Client struct {
+	Env         cactus.Environment             // Environment的返回值
+	WalletList  []string                       // WalletCodes的返回值
+	Coins       map[string]model.CoinInfo      // 币种元数据，按币种名称
+	Addresses   map[string][]model.AddressInfo // 地址及余额，按币种名称
+	Wallets     map[string][]model.CoinBalance // 钱包余额，按钱包编号
+	TxDetails   []model.TxDetailItem           // TxDetail返回的记录，按创建时间升序
+	TxSummaries []model.TxSummaryItem          // TxSummary返回的记录，按创建时间升序
+	Orders      []model.CreateOrderReq         // CreateOrder收到的订单
+}
+
+var _ cactus.Client = (*Client)(nil)
+
+// page 按offset、limit截取一页
+func page[T any](items []T, offset, limit *int) model.Page[T] {
+	p := model.Page[T]{Total: len(items)}
+	if offset != nil {
+		p.Offset = min(*offset, len(items))
+	}
+	end := len(items)
+	if limit != nil && *limit > 0 {
+		p.Limit = *limit
+		end = min(p.Offset+*limit, end)
+	}
+	p.List = items[p.Offset:end]
+	return p
+}
+
+// inRange 创建时间是否在[start, end]内，nil表示不限
+func inRange(ts model.Timestamp, start, end *model.Timestamp) bool {
+	return (start == nil || ts >= *start) && (end == nil || ts <= *end)
+}
+
+func (c *Client) Environment() cactus.Environment { return c.Env }
+
+func (c *Client) WalletCodes() []string { return c.WalletList }
+
+func (c *Client) GetCoinInfo(_ context.Context, coinName string) (*model.CoinInfo, error) {
+	coin, ok := c.Coins[coinName]
+	if !ok {
+		return nil, cactus.ErrCoinNotFound
+	}
+	return &coin, nil
+}
+
+func (c *Client) GetAddressList(_ context.Context, req *model.GetAddressesReq) (*model.GetAddressesResp, error) {
+	coin := c.Coins[req.CoinName]
+	var list []model.AddressInfo
+	for _, info := range c.Addresses[req.CoinName] {
+		if req.KeyWord != nil && info.Address != *req.KeyWord {
+			continue
+		}
+		if req.MinBalance != nil && coin.ToMinUnit(info.TotalAmount).IntPart() < *req.MinBalance {
+			continue
+		}
+		list = append(list, info)
+	}
+	return &model.GetAddressesResp{Data: page(list, req.Offset, req.Limit)}, nil
+}
+
+func (c *Client) GetAddressBalance(_ context.Context, req *model.GetAddressBalanceReq) (*model.AddressBalance, error) {
+	for _, info := range c.Addresses[req.CoinName] {
+		if info.Address == req.Address {
+			return &model.AddressBalance{WalletCode: info.WalletCode, Address: info.Address, CoinBalance: model.CoinBalance{
+				CoinName:  info.CoinName,
+				Total:     info.TotalAmount,
+				Available: info.AvailableAmount,
+				Frozen:    info.FreezeAmount,
+			}}, nil
+		}
+	}
+	return nil, cactus.ErrAddressNotFound
+}
+
+func (c *Client) GetWalletBalance(_ context.Context, req *model.GetWalletBalanceReq) (*model.WalletBalance, error) {
+	return &model.WalletBalance{WalletCode: req.WalletCode, Balances: c.Wallets[req.WalletCode]}, nil
+}
+
+func (c *Client) TxDetail(_ context.Context, req *model.TxDetailReq) (*model.TxDetailResp, error) {
+	var list []model.TxDetailItem
+	for _, item := range c.TxDetails {
+		if inRange(item.CreateTimeStamp, req.StartTime, req.EndTime) {
+			list = append(list, item)
+		}
+	}
+	return &model.TxDetailResp{Data: page(list, req.Offset, req.Limit)}, nil
+}
+
+func (c *Client) TxSummary(_ context.Context, req *model.TxSummaryReq) (*model.TxSummaryResp, error) {
+	var list []model.TxSummaryItem
+	for _, item := range c.TxSummaries {
+		if inRange(item.CreateTimeStamp, req.StartTime, req.EndTime) {
+			list = append(list, item)
+		}
+	}
+	return &model.TxSummaryResp{Data: page(list, req.Offset, req.Limit)}, nil
+}
+
+func (c *Client) CreateOrder(_ context.Context, req *model.CreateOrderReq) (*model.CreateOrderResp, error) {
+	c.Orders = append(c.Orders, *req)
+	return &model.CreateOrderResp{Data: model.CreateOrderResult{OrderNo: req.OrderNo}}, nil
+}
+
+func (c *Client) CheckAddress(context.Context, *model.CheckAddressReq) (*model.CheckAddressResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) TransferBetweenWallets(context.Context, *model.TransferReq) (*model.CreateOrderResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) GetOrder(context.Context, *model.GetOrderReq) (*model.GetOrderResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) ListOrders(context.Context, *model.ListOrdersReq) (*model.ListOrdersResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) CancelOrder(context.Context, *model.CancelOrderReq) (*model.CancelOrderResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) EstimateFee(context.Context, *model.EstimateFeeReq) (*model.EstimateFeeResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) CreateAddresses(context.Context, *model.CreateAddressesReq) (*model.CreateAddressesResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) UpdateAddressDescription(context.Context, *model.UpdateAddressDescriptionReq) (*model.UpdateAddressDescriptionResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) ListCoins(context.Context, *model.ListCoinsReq) (*model.ListCoinsResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) ListWallets(context.Context, *model.ListWalletsReq) (*model.ListWalletsResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) GetWallet(context.Context, *model.GetWalletReq) (*model.GetWalletResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) ListWhitelist(context.Context, *model.ListWhitelistReq) (*model.ListWhitelistResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) AddWhitelistAddresses(context.Context, *model.AddWhitelistAddressesReq) (*model.AddWhitelistAddressesResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) RemoveWhitelistAddresses(context.Context, *model.RemoveWhitelistAddressesReq) (*model.RemoveWhitelistAddressesResp, error) {
+	return nil, ErrNotStubbed
+}
+
+func (c *Client) Do(context.Context, string, string, url.Values, any, any) error {
+	return ErrNotStubbed
+}
+
+func (c *Client) GetPublicIP(context.Context) (string, error) {
+	return "", ErrNotStubbed
+}
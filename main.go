@@ -11,7 +11,11 @@ import (
 // 示例
 func main() {
 	ctx := context.Background()
-	client := cactus.NewClient()
+	client, err := cactus.NewClient()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	resp, err := client.CheckAddress(ctx, &model.CheckAddressReq{
 		Addresses: []string{"3SYQn32YG7XowiCzXKuXqnqBWtFvQDp3WeK36eE8rTEi"},
 		CoinName:  "USDT_SOL",
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// CircuitState 熔断器状态，作为gauge的取值
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = 0 // 正常放行
+	CircuitHalfOpen CircuitState = 1 // 试探放行
+	CircuitOpen     CircuitState = 2 // 熔断拒绝
+)
+
+// ClientMetrics Cactus客户端使用的全部指标
+type ClientMetrics struct {
+	requests        Counter   // 接口调用次数
+	requestDuration Histogram // 接口调用耗时
+	httpResponses   Counter   // 每次HTTP尝试的状态码
+	retries         Counter   // 重试次数
+	signDuration    Histogram // 签名耗时
+	circuitState    Gauge     // 熔断器状态
+	rateLimitWait   Gauge     // 限流器最近一次等待时长
+}
+
+// NewClientMetrics 在注册表上注册Cactus客户端的指标
+func NewClientMetrics(reg Registry) *ClientMetrics {
+	return &ClientMetrics{
+		requests: reg.Counter("cactus_requests_total",
+			"Cactus API calls by endpoint, HTTP status and Cactus response code.",
+			"endpoint", "status", "code"),
+		requestDuration: reg.Histogram("cactus_request_duration_seconds",
+			"Cactus API call latency including retries.", DefBuckets,
+			"endpoint"),
+		httpResponses: reg.Counter("cactus_http_responses_total",
+			"HTTP attempts by method and status code, 0 means a transport error.",
+			"method", "status"),
+		retries: reg.Counter("cactus_http_retries_total",
+			"HTTP attempts retried after a transport or 5xx error.",
+			"method"),
+		signDuration: reg.Histogram("cactus_signing_duration_seconds",
+			"Time spent signing a request.", []float64{.0001, .0005, .001, .005, .01, .05},
+		),
+		circuitState: reg.Gauge("cactus_circuit_breaker_state",
+			"Circuit breaker state: 0 closed, 1 half-open, 2 open.",
+			"name"),
+		rateLimitWait: reg.Gauge("cactus_rate_limiter_wait_seconds",
+			"Time the last request waited for the rate limiter.",
+			"name"),
+	}
+}
+
+// ObserveRequest 记录一次接口调用，status为0表示未拿到HTTP响应，code为空表示响应中没有Cactus返回码
+func (m *ClientMetrics) ObserveRequest(endpoint string, status int, code string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requests.Add(1, endpoint, strconv.Itoa(status), code)
+	m.requestDuration.Observe(d.Seconds(), endpoint)
+}
+
+// ObserveAttempt 记录一次HTTP尝试，attempt从0开始，大于0的尝试计为重试
+func (m *ClientMetrics) ObserveAttempt(method string, attempt, status int) {
+	if m == nil {
+		return
+	}
+	m.httpResponses.Add(1, method, strconv.Itoa(status))
+	if attempt > 0 {
+		m.retries.Add(1, method)
+	}
+}
+
+// ObserveSigning 记录一次签名耗时
+func (m *ClientMetrics) ObserveSigning(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.signDuration.Observe(d.Seconds())
+}
+
+// SetCircuitState 上报熔断器状态，httpclient.WithCircuitBreaker在状态切换时按主机调用
+func (m *ClientMetrics) SetCircuitState(name string, state CircuitState) {
+	if m == nil {
+		return
+	}
+	m.circuitState.Set(float64(state), name)
+}
+
+// SetRateLimiterWait 上报限流器等待时长，httpclient.WithRateLimit在放行每次尝试时按主机调用
+func (m *ClientMetrics) SetRateLimiterWait(name string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.rateLimitWait.Set(d.Seconds(), name)
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriteText 测试Prometheus文本格式输出
+func TestWriteText(t *testing.T) {
+	reg := NewRegistry()
+	m := NewClientMetrics(reg)
+
+	m.ObserveRequest("CreateOrder", 200, "0", 30*time.Millisecond)
+	m.ObserveRequest("CreateOrder", 200, "0", 2*time.Second)
+	m.ObserveAttempt("POST", 0, 502)
+	m.ObserveAttempt("POST", 1, 200)
+	m.SetCircuitState("cactus", CircuitOpen)
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, out, "# TYPE cactus_requests_total counter\n")
+	assert.Contains(t, out, `cactus_requests_total{endpoint="CreateOrder",status="200",code="0"} 2`)
+	assert.Contains(t, out, `cactus_request_duration_seconds_bucket{endpoint="CreateOrder",le="0.05"} 1`)
+	assert.Contains(t, out, `cactus_request_duration_seconds_bucket{endpoint="CreateOrder",le="+Inf"} 2`)
+	assert.Contains(t, out, `cactus_request_duration_seconds_count{endpoint="CreateOrder"} 2`)
+	assert.Contains(t, out, `cactus_http_responses_total{method="POST",status="502"} 1`)
+	assert.Contains(t, out, `cactus_http_retries_total{method="POST"} 1`)
+	assert.Contains(t, out, `cactus_circuit_breaker_state{name="cactus"} 2`)
+}
+
+// TestEscapeLabel 测试标签值转义
+func TestEscapeLabel(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapeLabel("a\"b\\c\nd"))
+}
@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry 指标注册表接口，可替换为其他监控系统的适配实现
+type Registry interface {
+	// Counter 注册（或取回已注册的）单调递增计数器
+	Counter(name, help string, labels ...string) Counter
+	// Histogram 注册（或取回已注册的）直方图，buckets为上界列表
+	Histogram(name, help string, buckets []float64, labels ...string) Histogram
+	// Gauge 注册（或取回已注册的）可增可减的仪表盘
+	Gauge(name, help string, labels ...string) Gauge
+}
+
+// Counter 计数器，labelValues需与注册时的labels一一对应
+type Counter interface {
+	Add(v float64, labelValues ...string)
+}
+
+// Histogram 直方图
+type Histogram interface {
+	Observe(v float64, labelValues ...string)
+}
+
+// Gauge 仪表盘
+type Gauge interface {
+	Set(v float64, labelValues ...string)
+}
+
+// DefBuckets 默认的耗时分桶（秒）
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// metricType 指标类型，取值与Prometheus文本格式中的TYPE一致
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// labelSep 拼接标签值作为series键时使用的分隔符
+const labelSep = "\xff"
+
+// series 单个标签组合下的取值
+type series struct {
+	labelValues []string
+	value       float64  // counter/gauge的值
+	counts      []uint64 // histogram每个桶的计数（非累计）
+	sum         float64  // histogram观测值之和
+	count       uint64   // histogram观测次数
+}
+
+// family 同名指标的集合
+type family struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// get 取回标签组合对应的series，不存在时创建
+func (f *family) get(labelValues []string) *series {
+	values := make([]string, len(f.labels))
+	copy(values, labelValues)
+	key := strings.Join(values, labelSep)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: values}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Add 实现Counter
+func (f *family) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return // 计数器只增不减
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.get(labelValues).value += v
+}
+
+// Set 实现Gauge
+func (f *family) Set(v float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.get(labelValues).value = v
+}
+
+// Observe 实现Histogram
+func (f *family) Observe(v float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.get(labelValues)
+	for i, upper := range f.buckets {
+		if v <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// snapshot 按标签值排序复制出所有series，保证输出顺序稳定
+func (f *family) snapshot() []series {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]series, 0, len(keys))
+	for _, k := range keys {
+		s := *f.series[k]
+		s.counts = append([]uint64(nil), s.counts...)
+		out = append(out, s)
+	}
+	return out
+}
+
+// MemoryRegistry 内存中的指标注册表，可通过Handler以Prometheus文本格式导出
+type MemoryRegistry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry 创建一个内存指标注册表
+func NewRegistry() *MemoryRegistry {
+	return &MemoryRegistry{families: make(map[string]*family)}
+}
+
+// register 注册指标，同名指标重复注册时返回已有的实例
+func (r *MemoryRegistry) register(name, help string, typ metricType, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		return f
+	}
+	if typ == typeHistogram {
+		buckets = append([]float64(nil), buckets...)
+		sort.Float64s(buckets)
+		if len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1], 1) {
+			buckets = append(buckets, math.Inf(1))
+		}
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// Counter 实现Registry
+func (r *MemoryRegistry) Counter(name, help string, labels ...string) Counter {
+	return r.register(name, help, typeCounter, nil, labels)
+}
+
+// Histogram 实现Registry
+func (r *MemoryRegistry) Histogram(name, help string, buckets []float64, labels ...string) Histogram {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	return r.register(name, help, typeHistogram, buckets, labels)
+}
+
+// Gauge 实现Registry
+func (r *MemoryRegistry) Gauge(name, help string, labels ...string) Gauge {
+	return r.register(name, help, typeGauge, nil, labels)
+}
+
+// sortedFamilies 按名称排序返回所有指标
+func (r *MemoryRegistry) sortedFamilies() []*family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].name < out[j].name })
+	return out
+}
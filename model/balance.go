@@ -0,0 +1,54 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+type GetWalletBalanceReq struct {
+	BID        string   // 业务线ID，为空时使用Bid
+	WalletCode string   // 钱包编号
+	CoinNames  []string // 只查询这些币种，为空时返回全部
+}
+
+type GetAddressBalanceReq struct {
+	BID        string // 业务线ID，为空时使用Bid
+	WalletCode string // 钱包编号，为空时使用ETHWallet
+	Address    string // 地址字符串
+	CoinName   string // 币种名称
+}
+
+// CoinBalance 单个币种的余额
+type CoinBalance struct {
+	CoinName  string          `json:"coin_name"`
+	Total     decimal.Decimal `json:"total"`     // 总额
+	Available decimal.Decimal `json:"available"` // 可用
+	Frozen    decimal.Decimal `json:"frozen"`    // 冻结
+}
+
+// Add 累加另一个同币种余额
+func (b CoinBalance) Add(other CoinBalance) CoinBalance {
+	return CoinBalance{
+		CoinName:  b.CoinName,
+		Total:     b.Total.Add(other.Total),
+		Available: b.Available.Add(other.Available),
+		Frozen:    b.Frozen.Add(other.Frozen),
+	}
+}
+
+// WalletBalance 钱包各币种余额
+type WalletBalance struct {
+	WalletCode string        `json:"wallet_code"`
+	WalletType string        `json:"wallet_type"`
+	Balances   []CoinBalance `json:"balances"`
+}
+
+// AddressBalance 单个地址的余额
+type AddressBalance struct {
+	WalletCode string `json:"wallet_code"`
+	Address    string `json:"address"`
+	CoinBalance
+}
+
+// BalanceReport 多个钱包的余额汇总
+type BalanceReport struct {
+	Wallets []WalletBalance `json:"wallets"` // 各钱包余额
+	Totals  []CoinBalance   `json:"totals"`  // 按币种汇总，按币种名称排序
+}
@@ -1,19 +1,19 @@
 package model
 
-import "github.com/shopspring/decimal"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type CheckAddressReq struct {
 	Addresses []string `json:"addresses"`
 	CoinName  string   `json:"coin_name"`
 }
-type CheckAddressResp struct {
-	Code       int      `json:"code"`
-	Message    string   `json:"message"`
-	Successful bool     `json:"successful"`
-	Data       []string `json:"data"`
-}
+type CheckAddressResp = Envelope[[]string]
 
 type CreateOrderReq struct {
+	BID                 string            `json:"-"` // 业务线ID，为空时使用Bid
 	FromAddress         *string           `json:"from_address,omitempty"`
 	FromWalletCode      string            `json:"from_wallet_code"`
 	CoinName            string            `json:"coin_name"`
@@ -34,103 +34,101 @@ type DestAddressItem struct {
 	ContractAggre    *bool           `json:"contract_aggre,omitempty"`
 }
 
-type CreateOrderResp struct {
-	Code       int    `json:"code"`
-	Message    string `json:"message"`
-	Successful bool   `json:"successful"`
-	Data       struct {
-		OrderNo string `json:"order_no"`
-	} `json:"data"`
+type CreateOrderResp = Envelope[CreateOrderResult]
+
+// CreateOrderResult 提币订单创建结果
+type CreateOrderResult struct {
+	OrderNo string `json:"order_no"`
 }
 
 type TxSummaryReq struct {
-	CoinName        string   `json:"coin_name"`
-	TxTypes         []string `json:"tx_types,omitempty"`
-	Addresses       []string `json:"addresses,omitempty"`
-	Offset          *int     `json:"offset,omitempty"`
-	Limit           *int     `json:"limit,omitempty"`
-	CreateTimeOrder *int     `json:"create_time_order,omitempty"`
-	StartTime       *int64   `json:"start_time,omitempty"`
-	EndTime         *int64   `json:"end_time,omitempty"`
-}
-
-type TxSummaryResp struct {
-	Code       int    `json:"code"`
-	Message    string `json:"message"`
-	Successful bool   `json:"successful"` // 使用指针处理可能为null的情况
-	Data       struct {
-		Total  int `json:"total"`
-		Offset int `json:"offset"`
-		Limit  int `json:"limit"`
-		List   []struct {
-			WalletCode      string  `json:"wallet_code"`
-			Chain           string  `json:"chain,omitempty"` // 根据文档补充
-			WalletType      string  `json:"wallet_type"`     // MIXED_ADDRESS/SEGREGATED_ADDRESS
-			CoinName        string  `json:"coin_name"`
-			OrderNo         string  `json:"order_no"`
-			BlockHeight     int64   `json:"block_height"`
-			TxID            string  `json:"tx_id"`
-			TxType          string  `json:"tx_type"`        // 枚举值参考文档
-			Amount          float64 `json:"amount"`         // 使用string处理大数/精度
-			WalletBalance   float64 `json:"wallet_balance"` // 同上
-			RemarkDetail    string  `json:"remark_detail"`
-			TxTimeStamp     int64   `json:"tx_time_stamp"` // 时间戳用int64
-			CreateTimeStamp int64   `json:"create_time_stamp"`
-		} `json:"list"`
-	} `json:"data"`
+	BID             string     `json:"-"` // 业务线ID，为空时使用Bid
+	WalletCode      string     `json:"-"` // 钱包编号，为空时使用ETHWallet
+	CoinName        string     `json:"coin_name"`
+	TxTypes         []string   `json:"tx_types,omitempty"`
+	Addresses       []string   `json:"addresses,omitempty"`
+	Offset          *int       `json:"offset,omitempty"`
+	Limit           *int       `json:"limit,omitempty"`
+	CreateTimeOrder *int       `json:"create_time_order,omitempty"`
+	StartTime       *Timestamp `json:"start_time,omitempty"`
+	EndTime         *Timestamp `json:"end_time,omitempty"`
+}
+
+// WithTimeRange 按创建时间[start, end)过滤，零值表示不限
+func (r *TxSummaryReq) WithTimeRange(start, end time.Time) *TxSummaryReq {
+	r.StartTime, r.EndTime = timeRange(start, end)
+	return r
+}
+
+type TxSummaryResp = Envelope[Page[TxSummaryItem]]
+
+// TxSummaryItem 钱包交易记录概要中的一条记录
+type TxSummaryItem struct {
+	WalletCode      string          `json:"wallet_code"`
+	Chain           string          `json:"chain,omitempty"` // 根据文档补充
+	WalletType      string          `json:"wallet_type"`     // MIXED_ADDRESS/SEGREGATED_ADDRESS
+	CoinName        string          `json:"coin_name"`
+	OrderNo         string          `json:"order_no"`
+	BlockHeight     int64           `json:"block_height"`
+	TxID            string          `json:"tx_id"`
+	TxType          string          `json:"tx_type"`        // 枚举值参考文档
+	Amount          decimal.Decimal `json:"amount"`         // 数字或字符串均可，不丢失精度
+	WalletBalance   decimal.Decimal `json:"wallet_balance"` // 同上
+	RemarkDetail    string          `json:"remark_detail"`
+	TxTimeStamp     Timestamp       `json:"tx_time_stamp"` // 毫秒时间戳
+	CreateTimeStamp Timestamp       `json:"create_time_stamp"`
 }
 
 type TxDetailReq struct {
-	BID             string   `json:"-"`                   //业务线ID
-	WalletCode      string   `json:"-"`                   //钱包地址
-	CoinName        string   `json:"coin_name,omitempty"` //币种名称
-	TxTypes         []string `json:"tx_types,omitempty"`  // 可选
-	Addresses       []string `json:"addresses,omitempty"` // 可选
-	ID              int64    `json:"id,omitempty"`        // 指针处理可选整型
-	TxID            *string  `json:"tx_id,omitempty"`
-	OrderNo         string   `json:"order_no,omitempty"`
-	Offset          *int     `json:"offset,omitempty"`            // 默认0
-	Limit           *int     `json:"limit,omitempty"`             // 默认10
-	CreateTimeOrder *int     `json:"create_time_order,omitempty"` // 0=降序 1=升序
-	StartTime       *int64   `json:"start_time,omitempty"`        // 时间戳用int64
-	EndTime         *int64   `json:"end_time,omitempty"`
-}
-
-type TxDetailResp struct {
-	Code       int    `json:"code"`
-	Message    string `json:"message"`
-	Successful bool   `json:"successful"` // 处理可能为null的布尔值
-	Data       struct {
-		Total  int `json:"total"`
-		Offset int `json:"offset"`
-		Limit  int `json:"limit"`
-		List   []struct {
-			ID              int             `json:"id"`
-			DomainID        string          `json:"domain_id"`
-			WalletCode      string          `json:"wallet_code"`
-			WalletType      string          `json:"wallet_type"` // MIXED_ADDRESS/SEGREGATED_ADDRESS
-			CoinName        string          `json:"coin_name"`
-			OrderNo         string          `json:"order_no,omitempty"`
-			BlockHeight     int64           `json:"block_height"`
-			ConfirmRatio    string          `json:"confirm_ratio,omitempty"`
-			TxID            string          `json:"tx_id"`
-			TxSize          int64           `json:"tx_size"`
-			TxType          string          `json:"tx_type"` // 枚举值参考文档
-			WithdrawAmount  decimal.Decimal `json:"withdraw_amount,omitempty"`
-			GasPrice        *string         `json:"gas_price,omitempty"`
-			GasLimit        *string         `json:"gas_limit,omitempty"`
-			TxFee           decimal.Decimal `json:"tx_fee"`
-			MinerReward     *string         `json:"miner_reward,omitempty"`
-			DepositAmount   decimal.Decimal `json:"deposit_amount"`
-			WalletBalance   float64         `json:"wallet_balance"`
-			TxStatus        string          `json:"tx_status"` // 状态枚举
-			RemarkDetail    *string         `json:"remark_detail,omitempty"`
-			TxTimeStamp     int64           `json:"tx_time_stamp"` // 时间戳用int64
-			CreateTimeStamp int64           `json:"create_time_stamp"`
-			Vins            []Vin           `json:"vins"`
-			Vouts           []Vout          `json:"vouts"`
-		} `json:"list"`
-	} `json:"data"`
+	BID             string     `json:"-"`                   //业务线ID
+	WalletCode      string     `json:"-"`                   //钱包地址
+	CoinName        string     `json:"coin_name,omitempty"` //币种名称
+	TxTypes         []string   `json:"tx_types,omitempty"`  // 可选
+	Addresses       []string   `json:"addresses,omitempty"` // 可选
+	ID              int64      `json:"id,omitempty"`        // 指针处理可选整型
+	TxID            *string    `json:"tx_id,omitempty"`
+	OrderNo         string     `json:"order_no,omitempty"`
+	Offset          *int       `json:"offset,omitempty"`            // 默认0
+	Limit           *int       `json:"limit,omitempty"`             // 默认10
+	CreateTimeOrder *int       `json:"create_time_order,omitempty"` // 0=降序 1=升序
+	StartTime       *Timestamp `json:"start_time,omitempty"`        // 毫秒时间戳
+	EndTime         *Timestamp `json:"end_time,omitempty"`
+}
+
+// WithTimeRange 按创建时间[start, end)过滤，零值表示不限
+func (r *TxDetailReq) WithTimeRange(start, end time.Time) *TxDetailReq {
+	r.StartTime, r.EndTime = timeRange(start, end)
+	return r
+}
+
+type TxDetailResp = Envelope[Page[TxDetailItem]]
+
+// TxDetailItem 钱包记录明细中的一条记录
+type TxDetailItem struct {
+	ID              int             `json:"id"`
+	DomainID        string          `json:"domain_id"`
+	WalletCode      string          `json:"wallet_code"`
+	WalletType      string          `json:"wallet_type"` // MIXED_ADDRESS/SEGREGATED_ADDRESS
+	CoinName        string          `json:"coin_name"`
+	OrderNo         string          `json:"order_no,omitempty"`
+	BlockHeight     int64           `json:"block_height"`
+	ConfirmRatio    string          `json:"confirm_ratio,omitempty"`
+	TxID            string          `json:"tx_id"`
+	TxSize          int64           `json:"tx_size"`
+	TxType          string          `json:"tx_type"` // 枚举值参考文档
+	WithdrawAmount  decimal.Decimal `json:"withdraw_amount,omitempty"`
+	GasPrice        *string         `json:"gas_price,omitempty"`
+	GasLimit        *string         `json:"gas_limit,omitempty"`
+	TxFee           decimal.Decimal `json:"tx_fee"`
+	MinerReward     *string         `json:"miner_reward,omitempty"`
+	DepositAmount   decimal.Decimal `json:"deposit_amount"`
+	WalletBalance   decimal.Decimal `json:"wallet_balance"`
+	TxStatus        string          `json:"tx_status"` // 状态枚举
+	RemarkDetail    *string         `json:"remark_detail,omitempty"`
+	TxTimeStamp     Timestamp       `json:"tx_time_stamp"` // 毫秒时间戳
+	CreateTimeStamp Timestamp       `json:"create_time_stamp"`
+	Vins            []Vin           `json:"vins"`
+	Vouts           []Vout          `json:"vouts"`
 }
 
 // Vin 付款方地址详情
@@ -139,7 +137,7 @@ type Vin struct {
 	Index    int             `json:"idx"`
 	Tag      *string         `json:"tag,omitempty"`
 	Amount   decimal.Decimal `json:"amount,omitempty"`
-	Balance  float64         `json:"balance,omitempty"`
+	Balance  decimal.Decimal `json:"balance,omitempty"`
 	IsChange int             `json:"is_change"`
 	Desc     *string         `json:"desc,omitempty"`
 }
@@ -150,12 +148,14 @@ type Vout struct {
 	Index    int             `json:"idx"`
 	Tag      *string         `json:"tag,omitempty"`
 	Amount   decimal.Decimal `json:"amount"`
-	Balance  float64         `json:"balance"`
+	Balance  decimal.Decimal `json:"balance"`
 	IsChange int             `json:"is_change"`
 	Desc     *string         `json:"desc,omitempty"`
 }
 
 type GetAddressesReq struct {
+	BID        string `json:"-"` // 业务线ID，为空时使用Bid
+	WalletCode string `json:"-"` // 钱包编号，为空时使用ETHWallet
 	// 查询参数（使用指针和 omitempty 处理可选性）
 	CoinName            string  `json:"coin_name"`                       // 币种名称
 	HideNoCoinAddress   *string `json:"hide_no_coin_address,omitempty"`  // 是否隐藏无币地址（"true"/"false"）
@@ -168,51 +168,51 @@ type GetAddressesReq struct {
 	ManageWalletAddress *bool   `json:"manage_wallet_address,omitempty"` // 是否查询ETH管理地址
 }
 
-type GetAddressesResp struct {
-	Code       int    `json:"code"`       // 状态码（0=成功）
-	Message    string `json:"message"`    // 错误信息
-	Successful bool   `json:"successful"` // 请求状态（可能为 null）
-	Data       struct {
-		Offset int `json:"offset"` // 当前偏移量
-		Limit  int `json:"limit"`  // 每页限制
-		Total  int `json:"total"`  // 总数
-		List   []struct {
-			DomainID         string  `json:"domain_id"`          // 企业 Domain ID
-			BID              string  `json:"b_id"`               // 业务线 ID
-			WalletCode       string  `json:"wallet_code"`        // 钱包编号
-			WalletType       string  `json:"wallet_type"`        // 钱包类型（MIXED/SEGREGATED）
-			Address          string  `json:"address"`            // 地址字符串
-			AddressType      string  `json:"address_type"`       // 地址类型（NORMAL_ADDRESS）
-			AddressStorage   string  `json:"address_storage"`    // 存储类型（COLD/HOT）
-			CoinName         string  `json:"coin_name"`          // 币种名称（如 BTC）
-			BCHAddressFormat *string `json:"bch_address_format"` // BCH 格式（CashAddr/Legacy）
-			Description      string  `json:"description"`        // 地址描述
-			FreezeAmount     float64 `json:"freeze_amount"`      // 冻结金额（string 避免精度丢失）
-			TotalAmount      float64 `json:"total_amount"`       // 总金额（string 避免精度丢失）
-			AvailableAmount  float64 `json:"available_amount"`   // 可用金额（可选字段）
-		} `json:"list"` // 地址列表
-	} `json:"data"` // 数据主体
-}
-
-type AddressList struct {
-	Offset int           `json:"offset"` // 当前偏移量
-	Limit  int           `json:"limit"`  // 每页限制
-	Total  int           `json:"total"`  // 总数
-	List   []AddressInfo `json:"list"`   // 地址列表
-}
+type GetAddressesResp = Envelope[AddressList]
 
+// AddressList 地址分页列表
+type AddressList = Page[AddressInfo]
+
+// AddressInfo 地址详情
 type AddressInfo struct {
-	DomainID         string  `json:"domain_id"`          // 企业 Domain ID
-	BID              string  `json:"b_id"`               // 业务线 ID
-	WalletCode       string  `json:"wallet_code"`        // 钱包编号
-	WalletType       string  `json:"wallet_type"`        // 钱包类型（MIXED/SEGREGATED）
-	Address          string  `json:"address"`            // 地址字符串
-	AddressType      string  `json:"address_type"`       // 地址类型（NORMAL_ADDRESS）
-	AddressStorage   string  `json:"address_storage"`    // 存储类型（COLD/HOT）
-	CoinName         string  `json:"coin_name"`          // 币种名称（如 BTC）
-	BCHAddressFormat *string `json:"bch_address_format"` // BCH 格式（CashAddr/Legacy）
-	Description      string  `json:"description"`        // 地址描述
-	FreezeAmount     float64 `json:"freeze_amount"`      // 冻结金额（string 避免精度丢失）
-	TotalAmount      float64 `json:"total_amount"`       // 总金额（string 避免精度丢失）
-	AvailableAmount  float64 `json:"available_amount"`   // 可用金额（可选字段）
+	DomainID         string          `json:"domain_id"`          // 企业 Domain ID
+	BID              string          `json:"b_id"`               // 业务线 ID
+	WalletCode       string          `json:"wallet_code"`        // 钱包编号
+	WalletType       string          `json:"wallet_type"`        // 钱包类型（MIXED/SEGREGATED）
+	Address          string          `json:"address"`            // 地址字符串
+	AddressType      string          `json:"address_type"`       // 地址类型（NORMAL_ADDRESS）
+	AddressStorage   string          `json:"address_storage"`    // 存储类型（COLD/HOT）
+	CoinName         string          `json:"coin_name"`          // 币种名称（如 BTC）
+	BCHAddressFormat *string         `json:"bch_address_format"` // BCH 格式（CashAddr/Legacy）
+	Description      string          `json:"description"`        // 地址描述
+	FreezeAmount     decimal.Decimal `json:"freeze_amount"`      // 冻结金额（数字或字符串均可，不丢失精度）
+	TotalAmount      decimal.Decimal `json:"total_amount"`       // 总金额
+	AvailableAmount  decimal.Decimal `json:"available_amount"`   // 可用金额（可选字段）
 }
+
+// BCH 地址格式
+const (
+	BCHAddressFormatCashAddr = "CashAddr"
+	BCHAddressFormatLegacy   = "Legacy"
+)
+
+type CreateAddressesReq struct {
+	BID              string  `json:"-"`                            // 业务线ID，为空时使用Bid
+	WalletCode       string  `json:"-"`                            // 钱包编号
+	CoinName         string  `json:"coin_name"`                    // 币种名称
+	AddressNum       int     `json:"address_num"`                  // 生成地址数量
+	Description      *string `json:"description,omitempty"`        // 地址描述
+	BCHAddressFormat *string `json:"bch_address_format,omitempty"` // BCH 格式（CashAddr/Legacy）
+}
+
+type CreateAddressesResp = Envelope[[]AddressInfo] // Data为新生成的地址
+
+type UpdateAddressDescriptionReq struct {
+	BID         string `json:"-"`           // 业务线ID，为空时使用Bid
+	WalletCode  string `json:"-"`           // 钱包编号
+	Address     string `json:"-"`           // 地址字符串
+	CoinName    string `json:"coin_name"`   // 币种名称
+	Description string `json:"description"` // 新的地址描述
+}
+
+type UpdateAddressDescriptionResp = Envelope[AddressInfo] // Data为更新后的地址
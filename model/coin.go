@@ -0,0 +1,37 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+type ListCoinsReq struct {
+	BID       string   `json:"b_id"`                 // 业务线ID，为空时使用Bid
+	CoinNames []string `json:"coin_names,omitempty"` // 只查询这些币种
+	Chain     string   `json:"chain,omitempty"`      // 只查询该链上的币种
+}
+
+type ListCoinsResp = Envelope[[]CoinInfo]
+
+// CoinInfo 币种元数据
+type CoinInfo struct {
+	CoinName          string          `json:"coin_name"`                  // 币种名称（如 USDT_SOL）
+	Chain             string          `json:"chain"`                      // 所在链
+	ContractAddress   string          `json:"contract_address,omitempty"` // 合约地址，原生币为空
+	Decimals          int32           `json:"decimals"`                   // 精度
+	SupportMemo       bool            `json:"support_memo"`               // 提币是否支持memo/tag
+	MinWithdrawAmount decimal.Decimal `json:"min_withdraw_amount"`        // 最小提币数量
+	FeeCoinName       string          `json:"fee_coin_name"`              // 支付手续费的币种（如 ETH）
+}
+
+// IsToken 是否为合约代币
+func (c CoinInfo) IsToken() bool {
+	return c.ContractAddress != ""
+}
+
+// ToMinUnit 把币数量换算成最小单位（如 wei）
+func (c CoinInfo) ToMinUnit(amount decimal.Decimal) decimal.Decimal {
+	return amount.Shift(c.Decimals).Truncate(0)
+}
+
+// FromMinUnit 把最小单位换算成币数量
+func (c CoinInfo) FromMinUnit(amount decimal.Decimal) decimal.Decimal {
+	return amount.Shift(-c.Decimals)
+}
@@ -0,0 +1,17 @@
+package model
+
+// Envelope Cactus接口统一的响应结构，Code为0表示成功
+type Envelope[T any] struct {
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	Successful bool   `json:"successful"` // 可能为null
+	Data       T      `json:"data"`
+}
+
+// Page 分页列表
+type Page[T any] struct {
+	Offset int `json:"offset"` // 当前偏移量
+	Limit  int `json:"limit"`  // 每页限制
+	Total  int `json:"total"`  // 总数
+	List   []T `json:"list"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+// 手续费档位
+const (
+	FeeLevelSlow   = "SLOW"
+	FeeLevelNormal = "NORMAL"
+	FeeLevelFast   = "FAST"
+)
+
+type EstimateFeeReq struct {
+	BID                 string            `json:"-"`                      // 业务线ID，为空时使用Bid
+	FromAddress         *string           `json:"from_address,omitempty"` // 指定出账地址
+	FromWalletCode      string            `json:"from_wallet_code"`       // 出账钱包
+	CoinName            string            `json:"coin_name"`              // 币种名称
+	DestAddressItemList []DestAddressItem `json:"dest_address_item_list"` // 与CreateOrderReq相同的收款列表
+}
+
+type EstimateFeeResp = Envelope[[]FeeEstimate] // Data为各档位的预估
+
+// FeeEstimate 单个手续费档位的预估
+type FeeEstimate struct {
+	Level        string           `json:"level"`               // 档位（SLOW/NORMAL/FAST）
+	FeeRateLevel float64          `json:"fee_rate_level"`      // 提交订单时填入CreateOrderReq.FeeRateLevel的值
+	FeeRate      float64          `json:"fee_rate"`            // 费率，提交订单时可填入CreateOrderReq.FeeRate
+	Fee          decimal.Decimal  `json:"fee"`                 // 预估手续费
+	FeeCoinName  string           `json:"fee_coin_name"`       // 手续费币种
+	GasPrice     *decimal.Decimal `json:"gas_price,omitempty"` // EVM 链的 gas price
+	GasLimit     *decimal.Decimal `json:"gas_limit,omitempty"` // EVM 链的 gas limit
+
+	// 以下字段由客户端计算，不来自接口
+	Amount decimal.Decimal            `json:"-"` // 收款金额合计
+	Total  *decimal.Decimal           `json:"-"` // 总花费Amount+Fee，只在手续费币种与提币币种相同时设置，否则为nil
+	Totals map[string]decimal.Decimal `json:"-"` // 按币种的总花费（如USDT_ETH为Amount、ETH为Fee），手续费币种未知时不含手续费
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderStatus 提币订单状态
+type OrderStatus string
+
+const (
+	OrderStatusAuditing     OrderStatus = "AUDITING"     // 待审批
+	OrderStatusApproved     OrderStatus = "APPROVED"     // 已审批，待广播
+	OrderStatusBroadcasting OrderStatus = "BROADCASTING" // 已广播，待确认
+	OrderStatusSuccess      OrderStatus = "SUCCESS"      // 已上链确认
+	OrderStatusFailed       OrderStatus = "FAILED"       // 失败
+	OrderStatusRejected     OrderStatus = "REJECTED"     // 审批拒绝
+	OrderStatusCancelled    OrderStatus = "CANCELLED"    // 已取消
+)
+
+// Cancellable 订单在审批通过或广播之前才可以取消
+func (s OrderStatus) Cancellable() bool {
+	return s == OrderStatusAuditing
+}
+
+// Final 订单是否已处于终态
+func (s OrderStatus) Final() bool {
+	switch s {
+	case OrderStatusSuccess, OrderStatusFailed, OrderStatusRejected, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// OrderInfo 提币订单详情
+type OrderInfo struct {
+	OrderNo             string            `json:"order_no"`               // 订单号
+	BID                 string            `json:"b_id"`                   // 业务线 ID
+	FromWalletCode      string            `json:"from_wallet_code"`       // 出账钱包
+	FromAddress         *string           `json:"from_address,omitempty"` // 出账地址
+	CoinName            string            `json:"coin_name"`              // 币种名称
+	Status              OrderStatus       `json:"status"`                 // 订单状态
+	DestAddressItemList []DestAddressItem `json:"dest_address_item_list"` // 收款列表
+	TxID                string            `json:"tx_id,omitempty"`        // 广播后的交易哈希
+	TxFee               decimal.Decimal   `json:"tx_fee"`                 // 手续费
+	FeeRateLevel        float64           `json:"fee_rate_level,omitempty"`
+	Description         *string           `json:"description,omitempty"`
+	CreateTimeStamp     Timestamp         `json:"create_time_stamp"`
+	UpdateTimeStamp     Timestamp         `json:"update_time_stamp"`
+}
+
+type GetOrderReq struct {
+	BID     string `json:"-"` // 业务线ID，为空时使用Bid
+	OrderNo string `json:"-"` // 订单号
+}
+
+type GetOrderResp = Envelope[OrderInfo]
+
+type ListOrdersReq struct {
+	BID        string        `json:"-"`                     // 业务线ID，为空时使用Bid
+	Statuses   []OrderStatus `json:"status,omitempty"`      // 按状态过滤
+	CoinName   string        `json:"coin_name,omitempty"`   // 按币种过滤
+	WalletCode string        `json:"wallet_code,omitempty"` // 按出账钱包过滤
+	StartTime  *Timestamp    `json:"start_time,omitempty"`  // 创建时间下限
+	EndTime    *Timestamp    `json:"end_time,omitempty"`    // 创建时间上限
+	Offset     *int          `json:"offset,omitempty"`
+	Limit      *int          `json:"limit,omitempty"`
+}
+
+// WithTimeRange 按创建时间[start, end)过滤，零值表示不限
+func (r *ListOrdersReq) WithTimeRange(start, end time.Time) *ListOrdersReq {
+	r.StartTime, r.EndTime = timeRange(start, end)
+	return r
+}
+
+type ListOrdersResp = Envelope[Page[OrderInfo]]
+
+type CancelOrderReq struct {
+	BID     string  `json:"-"`                // 业务线ID，为空时使用Bid
+	OrderNo string  `json:"-"`                // 订单号
+	Reason  *string `json:"reason,omitempty"` // 取消原因
+}
+
+type CancelOrderResp = Envelope[CancelOrderResult]
+
+// CancelOrderResult 取消订单结果
+type CancelOrderResult struct {
+	OrderNo string      `json:"order_no"`
+	Status  OrderStatus `json:"status"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Timestamp Cactus接口中的毫秒时间戳，JSON中为整数，对外通过Time()提供time.Time，0表示未设置
+type Timestamp int64
+
+var timestampType = reflect.TypeOf(Timestamp(0))
+
+// NewTimestamp 把time.Time转为毫秒时间戳，零值转为0
+func NewTimestamp(t time.Time) Timestamp {
+	if t.IsZero() {
+		return 0
+	}
+	return Timestamp(t.UnixMilli())
+}
+
+// TimestampOf 返回指向时间戳的指针，零值返回nil，便于填写可选的请求参数
+func TimestampOf(t time.Time) *Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	ts := NewTimestamp(t)
+	return &ts
+}
+
+// Time 返回对应的UTC时间，0返回time.Time零值
+func (ts Timestamp) Time() time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ts)).UTC()
+}
+
+// IsZero 是否未设置
+func (ts Timestamp) IsZero() bool {
+	return ts == 0
+}
+
+// String 以RFC3339输出，0输出空串
+func (ts Timestamp) String() string {
+	if ts == 0 {
+		return ""
+	}
+	return ts.Time().Format(time.RFC3339Nano)
+}
+
+// MarshalJSON 输出毫秒整数
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(ts), 10), nil
+}
+
+// UnmarshalJSON 接受毫秒整数、带引号的整数和null
+func (ts *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*ts = 0
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*ts = 0
+			return nil
+		}
+		data = []byte(s)
+	}
+	ms, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return &json.UnmarshalTypeError{Value: string(data), Type: timestampType}
+	}
+	*ts = Timestamp(ms)
+	return nil
+}
+
+// timeRange 把[start, end)转为接口的起止毫秒时间戳，结束时间包含在内所以减去1毫秒
+func timeRange(start, end time.Time) (*Timestamp, *Timestamp) {
+	var endTs *Timestamp
+	if !end.IsZero() {
+		endTs = TimestampOf(end.Add(-time.Millisecond))
+	}
+	return TimestampOf(start), endTs
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTimestampJSON 测试毫秒时间戳的编解码与时间范围
+func TestTimestampJSON(t *testing.T) {
+	var item TxDetailItem
+	require.NoError(t, json.Unmarshal([]byte(`{"tx_time_stamp":1735689600123,"create_time_stamp":"1735689600000"}`), &item))
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 123e6, time.UTC), item.TxTimeStamp.Time())
+	assert.Equal(t, Timestamp(1735689600000), item.CreateTimeStamp)
+	assert.Error(t, json.Unmarshal([]byte(`{"tx_time_stamp":"soon"}`), &item))
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := (&TxSummaryReq{CoinName: "ETH"}).WithTimeRange(start, start.AddDate(0, 1, 0))
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"coin_name":"ETH","start_time":1735689600000,"end_time":1738367999999}`, string(data))
+}
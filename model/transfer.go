@@ -0,0 +1,15 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+type TransferReq struct {
+	BID            string          // 业务线ID，为空时使用Bid
+	FromWalletCode string          // 出账钱包
+	ToWalletCode   string          // 入账钱包
+	ToAddress      string          // 入账地址，必须属于入账钱包，为空时从入账钱包的地址列表中选取
+	CoinName       string          // 币种名称
+	Amount         decimal.Decimal // 转账数量
+	OrderNo        string          // 订单号
+	Description    *string         // 订单描述
+	FeeRateLevel   float64         // 手续费档位，含义同CreateOrderReq
+}
@@ -0,0 +1,41 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+type ListWalletsReq struct {
+	BID              string   `json:"b_id"`                          // 业务线ID，为空时使用Bid
+	CoinNames        []string `json:"coin_names,omitempty"`          // 只返回持有这些币种的钱包
+	HideNoCoinWallet *bool    `json:"hide_no_coin_wallet,omitempty"` // 是否隐藏无币钱包
+	Offset           *int     `json:"offset,omitempty"`              // 分页偏移量
+	Limit            *int     `json:"limit,omitempty"`               // 每页数量
+}
+
+type ListWalletsResp = Envelope[Page[WalletInfo]]
+
+type GetWalletReq struct {
+	BID        string   `json:"-"`                    // 业务线ID，为空时使用Bid
+	WalletCode string   `json:"-"`                    // 钱包编号
+	CoinNames  []string `json:"coin_names,omitempty"` // 只返回这些币种的余额
+}
+
+type GetWalletResp = Envelope[WalletInfo]
+
+// WalletInfo 钱包详情
+type WalletInfo struct {
+	DomainID       string       `json:"domain_id"`       // 企业 Domain ID
+	BID            string       `json:"b_id"`            // 业务线 ID
+	WalletCode     string       `json:"wallet_code"`     // 钱包编号
+	WalletName     string       `json:"wallet_name"`     // 钱包名称
+	WalletType     string       `json:"wallet_type"`     // 钱包类型（MIXED_ADDRESS/SEGREGATED_ADDRESS）
+	AddressStorage string       `json:"address_storage"` // 存储类型（COLD/HOT）
+	Description    string       `json:"description"`     // 钱包描述
+	CoinList       []WalletCoin `json:"coin_list"`       // 各币种余额
+}
+
+// WalletCoin 钱包内单个币种的余额
+type WalletCoin struct {
+	CoinName        string          `json:"coin_name"`        // 币种名称
+	TotalAmount     decimal.Decimal `json:"total_amount"`     // 总金额
+	AvailableAmount decimal.Decimal `json:"available_amount"` // 可用金额
+	FreezeAmount    decimal.Decimal `json:"freeze_amount"`    // 冻结金额
+}
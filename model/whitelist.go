@@ -0,0 +1,37 @@
+package model
+
+import "encoding/json"
+
+// WhitelistAddress 提币白名单地址
+type WhitelistAddress struct {
+	CoinName        string    `json:"coin_name"`                   // 币种名称
+	Address         string    `json:"address"`                     // 地址字符串
+	MemoType        *string   `json:"memo_type,omitempty"`         // memo 类型
+	Memo            *string   `json:"memo,omitempty"`              // memo/tag
+	Label           string    `json:"label,omitempty"`             // 标签
+	CreateTimeStamp Timestamp `json:"create_time_stamp,omitempty"` // 添加时间
+}
+
+type ListWhitelistReq struct {
+	BID      string `json:"-"`                   // 业务线ID，为空时使用Bid
+	CoinName string `json:"coin_name,omitempty"` // 按币种过滤
+	KeyWord  string `json:"key_word,omitempty"`  // 按地址或标签搜索
+	Offset   *int   `json:"offset,omitempty"`
+	Limit    *int   `json:"limit,omitempty"`
+}
+
+type ListWhitelistResp = Envelope[Page[WhitelistAddress]]
+
+type AddWhitelistAddressesReq struct {
+	BID       string             `json:"-"`         // 业务线ID，为空时使用Bid
+	Addresses []WhitelistAddress `json:"addresses"` // 待添加的地址
+}
+
+type AddWhitelistAddressesResp = Envelope[[]WhitelistAddress] // Data为添加成功的地址
+
+type RemoveWhitelistAddressesReq struct {
+	BID       string             `json:"-"`         // 业务线ID，为空时使用Bid
+	Addresses []WhitelistAddress `json:"addresses"` // 待移除的地址，按币种、地址和memo匹配
+}
+
+type RemoveWhitelistAddressesResp = Envelope[json.RawMessage] // Data的结构未约定，按原样保留
@@ -0,0 +1,65 @@
+package reconcile
+
+import "go-cactus/model"
+
+// index 按订单号和交易哈希查找账本记录，每条记录只能匹配一次
+type index struct {
+	records []Record
+	used    []bool
+	byOrder map[string][]int
+	byTxID  map[string][]int
+}
+
+func newIndex(records []Record) *index {
+	idx := &index{
+		records: records,
+		used:    make([]bool, len(records)),
+		byOrder: make(map[string][]int),
+		byTxID:  make(map[string][]int),
+	}
+	for i, rec := range records {
+		if rec.OrderNo != "" {
+			idx.byOrder[rec.OrderNo] = append(idx.byOrder[rec.OrderNo], i)
+		}
+		if rec.TxID != "" {
+			idx.byTxID[rec.TxID] = append(idx.byTxID[rec.TxID], i)
+		}
+	}
+	return idx
+}
+
+// take 优先按订单号匹配，其次按交易哈希和币种匹配
+func (idx *index) take(item model.TxDetailItem) (Record, bool) {
+	if item.OrderNo != "" {
+		if i, ok := idx.first(idx.byOrder[item.OrderNo], item.CoinName); ok {
+			return idx.records[i], true
+		}
+	}
+	if item.TxID != "" {
+		if i, ok := idx.first(idx.byTxID[item.TxID], item.CoinName); ok {
+			return idx.records[i], true
+		}
+	}
+	return Record{}, false
+}
+
+func (idx *index) first(candidates []int, coinName string) (int, bool) {
+	for _, i := range candidates {
+		if !idx.used[i] && idx.records[i].CoinName == coinName {
+			idx.used[i] = true
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// remaining 返回未被匹配的记录
+func (idx *index) remaining() []Record {
+	var records []Record
+	for i, rec := range idx.records {
+		if !idx.used[i] {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
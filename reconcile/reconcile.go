@@ -0,0 +1,218 @@
+// Package reconcile 核对Cactus钱包记录与内部账本
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// Record 内部账本中的一条记录
+type Record struct {
+	WalletCode string          // 钱包编号
+	CoinName   string          // 币种名称
+	OrderNo    string          // 提币订单号，充值记录可为空
+	TxID       string          // 链上交易哈希，未广播时可为空
+	Amount     decimal.Decimal // 到账或提币金额，不含手续费
+	Fee        decimal.Decimal // 手续费
+	Status     string          // 与Cactus tx_status对应的状态，为空时不比较
+}
+
+// Ledger 内部账本
+type Ledger interface {
+	// Records 返回钱包在[start, end)内的全部记录
+	Records(ctx context.Context, walletCode string, start, end time.Time) ([]Record, error)
+	// Balance 返回账本中钱包某币种在at时刻的余额，ok为false时不比较余额
+	Balance(ctx context.Context, walletCode, coinName string, at time.Time) (balance decimal.Decimal, ok bool, err error)
+}
+
+// Kind 差异类型
+type Kind string
+
+const (
+	MissingInLedger Kind = "missing_in_ledger" // Cactus有记录，账本没有
+	MissingInCactus Kind = "missing_in_cactus" // 账本有记录，Cactus没有
+	AmountMismatch  Kind = "amount_mismatch"   // 金额不一致
+	FeeMismatch     Kind = "fee_mismatch"      // 手续费不一致
+	StatusMismatch  Kind = "status_mismatch"   // 状态不一致
+	BalanceDrift    Kind = "balance_drift"     // 账本余额与Cactus WalletBalance不一致
+)
+
+// Mismatch 一条差异
+type Mismatch struct {
+	Kind       Kind   `json:"kind"`
+	WalletCode string `json:"wallet_code"`
+	CoinName   string `json:"coin_name"`
+	OrderNo    string `json:"order_no,omitempty"`
+	TxID       string `json:"tx_id,omitempty"`
+	Cactus     string `json:"cactus,omitempty"` // Cactus侧的值
+	Ledger     string `json:"ledger,omitempty"` // 账本侧的值
+}
+
+// Report 核对结果
+type Report struct {
+	Start      time.Time  `json:"start"`
+	End        time.Time  `json:"end"`
+	Wallets    []string   `json:"wallets"`
+	Checked    int        `json:"checked"` // 核对的Cactus记录数
+	Matched    int        `json:"matched"` // 完全一致的记录数
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+// Config 核对配置
+type Config struct {
+	BID         string          // 业务线ID，为空时使用客户端环境的业务线
+	WalletCodes []string        // 需要核对的钱包，为空时使用客户端Profile中的钱包
+	Start       time.Time       // 时间窗口起点（含）
+	End         time.Time       // 时间窗口终点（不含）
+	TxTypes     []string        // 记录类型，为空时使用TxDetail的默认值
+	Tolerance   decimal.Decimal // 金额和余额允许的误差
+}
+
+// Reconciler 核对器
+type Reconciler struct {
+	client cactus.Client
+	ledger Ledger
+	cfg    Config
+}
+
+// New 创建核对器，没有可核对的钱包时返回cactus.ErrNoWallets，避免空报告被误读为账目一致
+func New(client cactus.Client, ledger Ledger, cfg Config) (*Reconciler, error) {
+	if len(cfg.WalletCodes) == 0 {
+		cfg.WalletCodes = client.WalletCodes()
+	}
+	if len(cfg.WalletCodes) == 0 {
+		return nil, cactus.ErrNoWallets
+	}
+	return &Reconciler{client: client, ledger: ledger, cfg: cfg}, nil
+}
+
+// Run 逐个钱包拉取时间窗口内的记录明细并与账本核对
+func (r *Reconciler) Run(ctx context.Context) (*Report, error) {
+	if !r.cfg.End.After(r.cfg.Start) {
+		return nil, fmt.Errorf("invalid reconcile window %s - %s", r.cfg.Start, r.cfg.End)
+	}
+	report := &Report{Start: r.cfg.Start, End: r.cfg.End, Wallets: r.cfg.WalletCodes}
+	for _, walletCode := range r.cfg.WalletCodes {
+		if err := r.wallet(ctx, walletCode, report); err != nil {
+			return nil, fmt.Errorf("reconcile wallet %s: %w", walletCode, err)
+		}
+	}
+	return report, nil
+}
+
+func (r *Reconciler) wallet(ctx context.Context, walletCode string, report *Report) error {
+	req := model.TxDetailReq{BID: r.cfg.BID, WalletCode: walletCode, TxTypes: r.cfg.TxTypes}
+	req.WithTimeRange(r.cfg.Start, r.cfg.End)
+	items, err := cactus.ListAllTxDetails(ctx, r.client, req)
+	if err != nil {
+		return err
+	}
+	records, err := r.ledger.Records(ctx, walletCode, r.cfg.Start, r.cfg.End)
+	if err != nil {
+		return err
+	}
+
+	idx := newIndex(records)
+	latest := make(map[string]model.TxDetailItem) // 每个币种时间最晚的记录
+	for _, item := range items {
+		report.Checked++
+		if last, ok := latest[item.CoinName]; !ok || item.TxTimeStamp >= last.TxTimeStamp {
+			latest[item.CoinName] = item
+		}
+		rec, ok := idx.take(item)
+		if !ok {
+			report.Mismatches = append(report.Mismatches, mismatch(MissingInLedger, walletCode, item))
+			continue
+		}
+		diffs := r.compare(walletCode, item, rec)
+		if len(diffs) == 0 {
+			report.Matched++
+		}
+		report.Mismatches = append(report.Mismatches, diffs...)
+	}
+	for _, rec := range idx.remaining() {
+		report.Mismatches = append(report.Mismatches, Mismatch{
+			Kind:       MissingInCactus,
+			WalletCode: walletCode,
+			CoinName:   rec.CoinName,
+			OrderNo:    rec.OrderNo,
+			TxID:       rec.TxID,
+			Ledger:     rec.Amount.String(),
+		})
+	}
+	return r.drift(ctx, walletCode, latest, report)
+}
+
+// compare 比较一条已匹配的记录
+func (r *Reconciler) compare(walletCode string, item model.TxDetailItem, rec Record) []Mismatch {
+	var diffs []Mismatch
+	if amount := itemAmount(item); !r.equal(amount, rec.Amount) {
+		m := mismatch(AmountMismatch, walletCode, item)
+		m.Cactus, m.Ledger = amount.String(), rec.Amount.String()
+		diffs = append(diffs, m)
+	}
+	if !r.equal(item.TxFee, rec.Fee) {
+		m := mismatch(FeeMismatch, walletCode, item)
+		m.Cactus, m.Ledger = item.TxFee.String(), rec.Fee.String()
+		diffs = append(diffs, m)
+	}
+	if rec.Status != "" && !strings.EqualFold(item.TxStatus, rec.Status) {
+		m := mismatch(StatusMismatch, walletCode, item)
+		m.Cactus, m.Ledger = item.TxStatus, rec.Status
+		diffs = append(diffs, m)
+	}
+	return diffs
+}
+
+// drift 用每个币种最后一条记录的WalletBalance与账本余额比较
+func (r *Reconciler) drift(ctx context.Context, walletCode string, latest map[string]model.TxDetailItem, report *Report) error {
+	coins := make([]string, 0, len(latest))
+	for coin := range latest {
+		coins = append(coins, coin)
+	}
+	sort.Strings(coins)
+	for _, coin := range coins {
+		item := latest[coin]
+		balance, ok, err := r.ledger.Balance(ctx, walletCode, coin, item.TxTimeStamp.Time())
+		if err != nil {
+			return err
+		}
+		cactusBalance := item.WalletBalance
+		if ok && !r.equal(cactusBalance, balance) {
+			m := mismatch(BalanceDrift, walletCode, item)
+			m.Cactus, m.Ledger = cactusBalance.String(), balance.String()
+			report.Mismatches = append(report.Mismatches, m)
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) equal(a, b decimal.Decimal) bool {
+	return a.Sub(b).Abs().LessThanOrEqual(r.cfg.Tolerance)
+}
+
+// itemAmount 充值记录取到账金额，其余取提币金额
+func itemAmount(item model.TxDetailItem) decimal.Decimal {
+	if !item.DepositAmount.IsZero() {
+		return item.DepositAmount
+	}
+	return item.WithdrawAmount
+}
+
+func mismatch(kind Kind, walletCode string, item model.TxDetailItem) Mismatch {
+	return Mismatch{
+		Kind:       kind,
+		WalletCode: walletCode,
+		CoinName:   item.CoinName,
+		OrderNo:    item.OrderNo,
+		TxID:       item.TxID,
+	}
+}
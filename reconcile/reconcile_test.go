@@ -0,0 +1,79 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/internal/cactustest"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger struct {
+	records []Record
+	balance decimal.Decimal
+}
+
+func (l *fakeLedger) Records(context.Context, string, time.Time, time.Time) ([]Record, error) {
+	return l.records, nil
+}
+
+func (l *fakeLedger) Balance(context.Context, string, string, time.Time) (decimal.Decimal, bool, error) {
+	return l.balance, true, nil
+}
+
+// TestReconcilerRun 测试各类差异的识别
+func TestReconcilerRun(t *testing.T) {
+	d := decimal.RequireFromString
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := model.NewTimestamp(start)
+	client := &cactustest.Client{TxDetails: []model.TxDetailItem{
+		{CoinName: "ETH", TxID: "t1", DepositAmount: d("1"), TxStatus: "SUCCESS", WalletBalance: d("1"), TxTimeStamp: ms + 1, CreateTimeStamp: ms + 1},
+		{CoinName: "ETH", OrderNo: "o2", TxID: "t2", WithdrawAmount: d("0.5"), TxFee: d("0.01"), TxStatus: "SUCCESS", WalletBalance: d("0.49"), TxTimeStamp: ms + 2, CreateTimeStamp: ms + 2},
+		{CoinName: "ETH", OrderNo: "o3", WithdrawAmount: d("0.2"), TxStatus: "PENDING", WalletBalance: d("0.49"), TxTimeStamp: ms + 3, CreateTimeStamp: ms + 3},
+		{CoinName: "ETH", TxID: "t4", DepositAmount: d("3"), WalletBalance: d("3.49"), TxTimeStamp: ms + 4, CreateTimeStamp: ms + 4},
+	}}
+	ledger := &fakeLedger{
+		balance: d("0.49"),
+		records: []Record{
+			{CoinName: "ETH", TxID: "t1", Amount: d("1")},
+			{CoinName: "ETH", OrderNo: "o2", Amount: d("0.4"), Fee: d("0.01")},
+			{CoinName: "ETH", OrderNo: "o3", Amount: d("0.2"), Status: "success"},
+			{CoinName: "ETH", OrderNo: "o5", Amount: d("7")},
+		},
+	}
+	reconciler, err := New(client, ledger, Config{
+		WalletCodes: []string{"w1"},
+		Start:       start,
+		End:         start.AddDate(0, 1, 0),
+	})
+	require.NoError(t, err)
+	report, err := reconciler.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, 1, report.Matched)
+	kinds := make([]Kind, 0, len(report.Mismatches))
+	for _, m := range report.Mismatches {
+		kinds = append(kinds, m.Kind)
+	}
+	assert.Equal(t, []Kind{AmountMismatch, StatusMismatch, MissingInLedger, MissingInCactus, BalanceDrift}, kinds)
+	assert.Equal(t, "0.5", report.Mismatches[0].Cactus)
+	assert.Equal(t, "o5", report.Mismatches[3].OrderNo)
+	assert.Equal(t, "3.49", report.Mismatches[4].Cactus)
+}
+
+// TestNewNoWallets 测试没有指定钱包、客户端也没有配置钱包时拒绝创建核对器
+func TestNewNoWallets(t *testing.T) {
+	_, err := New(&cactustest.Client{}, &fakeLedger{}, Config{})
+	assert.ErrorIs(t, err, cactus.ErrNoWallets)
+
+	r, err := New(&cactustest.Client{WalletList: []string{"w1"}}, &fakeLedger{}, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"w1"}, r.cfg.WalletCodes)
+}
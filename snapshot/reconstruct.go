@@ -0,0 +1,102 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReconstructReq 历史余额推算参数
+type ReconstructReq struct {
+	BID        string        // 业务线ID，为空时使用客户端环境的业务线
+	WalletCode string        // 钱包编号
+	At         time.Time     // 需要推算余额的时刻
+	TxTypes    []string      // 参与回放的记录类型，为空时使用TxDetail的默认值
+	Lookback   time.Duration // 从At之前多久开始查询，覆盖创建早于At、上链晚于At的记录，默认24小时
+}
+
+// defaultLookback 查询记录明细时在At之前多取的时间
+const defaultLookback = 24 * time.Hour
+
+// settledStatus 已上链确认的记录状态，其余状态（失败、拒绝、处理中）没有改变余额
+const settledStatus = "SUCCESS"
+
+// Reconstruct 以当前余额为起点，倒序撤销At之后上链确认的充值、提币和手续费，推算钱包在At时刻各币种的总额。
+// 手续费计入该币种的手续费币种（如USDT_ETH的手续费计入ETH）。
+// 服务端按创建时间过滤，因此从At-Lookback开始查询，再按交易时间在本地过滤
+func Reconstruct(ctx context.Context, client cactus.Client, req ReconstructReq) (*Snapshot, error) {
+	current, err := client.GetWalletBalance(ctx, &model.GetWalletBalanceReq{BID: req.BID, WalletCode: req.WalletCode})
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[string]decimal.Decimal, len(current.Balances))
+	for _, b := range current.Balances {
+		totals[b.CoinName] = b.Total
+	}
+
+	lookback := req.Lookback
+	if lookback <= 0 {
+		lookback = defaultLookback
+	}
+	at := model.NewTimestamp(req.At)
+	feeCoins := make(map[string]string)
+	detailReq := model.TxDetailReq{BID: req.BID, WalletCode: req.WalletCode, TxTypes: req.TxTypes}
+	detailReq.WithTimeRange(req.At.Add(-lookback), time.Time{})
+	err = cactus.EachTxDetail(ctx, client, detailReq, func(item model.TxDetailItem) error {
+		if txTime(item) <= at || !strings.EqualFold(item.TxStatus, settledStatus) {
+			return nil
+		}
+		totals[item.CoinName] = totals[item.CoinName].Sub(item.DepositAmount).Add(item.WithdrawAmount)
+		if item.TxFee.IsZero() || !item.DepositAmount.IsZero() {
+			return nil // 充值的手续费由付款方承担
+		}
+		feeCoin, err := feeCoinOf(ctx, client, feeCoins, item.CoinName)
+		if err != nil {
+			return err
+		}
+		totals[feeCoin] = totals[feeCoin].Add(item.TxFee)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{Time: req.At.UTC(), WalletCode: req.WalletCode, Reconstructed: true}
+	for coin, total := range totals {
+		snap.Balances = append(snap.Balances, model.CoinBalance{CoinName: coin, Total: total})
+	}
+	sort.Slice(snap.Balances, func(i, j int) bool { return snap.Balances[i].CoinName < snap.Balances[j].CoinName })
+	return snap, nil
+}
+
+// txTime 优先使用交易时间，没有时使用创建时间
+func txTime(item model.TxDetailItem) model.Timestamp {
+	if item.TxTimeStamp != 0 {
+		return item.TxTimeStamp
+	}
+	return item.CreateTimeStamp
+}
+
+// feeCoinOf 查询币种的手续费币种，没有元数据时认为手续费就是该币种
+func feeCoinOf(ctx context.Context, client cactus.Client, cache map[string]string, coinName string) (string, error) {
+	if feeCoin, ok := cache[coinName]; ok {
+		return feeCoin, nil
+	}
+	feeCoin := coinName
+	info, err := client.GetCoinInfo(ctx, coinName)
+	switch {
+	case err == nil && info.FeeCoinName != "":
+		feeCoin = info.FeeCoinName
+	case err != nil && !errors.Is(err, cactus.ErrCoinNotFound):
+		return "", err
+	}
+	cache[coinName] = feeCoin
+	return feeCoin, nil
+}
@@ -0,0 +1,90 @@
+// Package snapshot 定期记录钱包余额快照，并根据记录明细推算历史余额
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+)
+
+// Snapshot 某个时刻一个钱包各币种的余额
+type Snapshot struct {
+	Time          time.Time           `json:"time"`
+	WalletCode    string              `json:"wallet_code"`
+	Balances      []model.CoinBalance `json:"balances"`
+	Reconstructed bool                `json:"reconstructed,omitempty"` // 由记录明细推算，只有Total有效
+}
+
+// Config 快照配置
+type Config struct {
+	BID         string        // 业务线ID，为空时使用客户端环境的业务线
+	WalletCodes []string      // 需要记录的钱包，为空时使用客户端Profile中的钱包
+	CoinNames   []string      // 只记录这些币种，为空时记录全部
+	Interval    time.Duration // 快照间隔，默认24小时，按UTC整点对齐
+	OnError     func(error)   // Run中单次快照失败时回调，为nil时忽略
+}
+
+// Snapshotter 按计划把余额快照写入Store
+type Snapshotter struct {
+	client cactus.Client
+	store  Store
+	cfg    Config
+	now    func() time.Time
+}
+
+// New 创建快照器，没有可记录的钱包时返回cactus.ErrNoWallets，避免一直空跑
+func New(client cactus.Client, store Store, cfg Config) (*Snapshotter, error) {
+	if len(cfg.WalletCodes) == 0 {
+		cfg.WalletCodes = client.WalletCodes()
+	}
+	if len(cfg.WalletCodes) == 0 {
+		return nil, cactus.ErrNoWallets
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	return &Snapshotter{client: client, store: store, cfg: cfg, now: time.Now}, nil
+}
+
+// Snapshot 立即为每个钱包记录一次快照
+func (s *Snapshotter) Snapshot(ctx context.Context) ([]Snapshot, error) {
+	now := s.now().UTC()
+	snaps := make([]Snapshot, 0, len(s.cfg.WalletCodes))
+	for _, walletCode := range s.cfg.WalletCodes {
+		balance, err := s.client.GetWalletBalance(ctx, &model.GetWalletBalanceReq{
+			BID:        s.cfg.BID,
+			WalletCode: walletCode,
+			CoinNames:  s.cfg.CoinNames,
+		})
+		if err != nil {
+			return snaps, err
+		}
+		snap := Snapshot{Time: now, WalletCode: walletCode, Balances: balance.Balances}
+		if err := s.store.Save(ctx, snap); err != nil {
+			return snaps, fmt.Errorf("save snapshot of %s: %w", walletCode, err)
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// Run 在每个Interval整点记录快照，直到ctx取消
+func (s *Snapshotter) Run(ctx context.Context) error {
+	for {
+		now := s.now()
+		next := now.Truncate(s.cfg.Interval).Add(s.cfg.Interval)
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if _, err := s.Snapshot(ctx); err != nil && s.cfg.OnError != nil {
+			s.cfg.OnError(err)
+		}
+	}
+}
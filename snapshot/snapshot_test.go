@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/internal/cactustest"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeClient 构造钱包w1的余额和记录明细，USDT_ETH的手续费币种为ETH
+func newFakeClient(balances []model.CoinBalance, items []model.TxDetailItem) *cactustest.Client {
+	return &cactustest.Client{
+		Coins:     map[string]model.CoinInfo{"USDT_ETH": {CoinName: "USDT_ETH", FeeCoinName: "ETH"}},
+		Wallets:   map[string][]model.CoinBalance{"w1": balances},
+		TxDetails: items,
+	}
+}
+
+// TestSnapshotFileStore 测试快照写入文件并读回
+func TestSnapshotFileStore(t *testing.T) {
+	client := &cactustest.Client{Wallets: map[string][]model.CoinBalance{
+		"w2": {{CoinName: "ETH", Total: decimal.RequireFromString("1.5")}},
+	}}
+	store := NewFileStore(filepath.Join(t.TempDir(), "snapshots.jsonl"))
+	s, err := New(client, store, Config{WalletCodes: []string{"w1", "w2"}})
+	require.NoError(t, err)
+	s.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	_, err = s.Snapshot(context.Background())
+	require.NoError(t, err)
+
+	snaps, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, snaps, 2)
+	assert.Equal(t, "w2", snaps[1].WalletCode)
+	assert.Equal(t, "1.5", snaps[1].Balances[0].Total.String())
+	assert.True(t, s.now().Equal(snaps[0].Time))
+}
+
+// TestNewNoWallets 测试没有指定钱包、客户端也没有配置钱包时拒绝创建快照器
+func TestNewNoWallets(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "snapshots.jsonl"))
+	_, err := New(&cactustest.Client{}, store, Config{})
+	assert.ErrorIs(t, err, cactus.ErrNoWallets)
+
+	s, err := New(&cactustest.Client{WalletList: []string{"w1"}}, store, Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"w1"}, s.cfg.WalletCodes)
+}
+
+// TestReconstruct 测试倒推历史余额，手续费计入手续费币种
+func TestReconstruct(t *testing.T) {
+	d := decimal.RequireFromString
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := model.NewTimestamp(at)
+	client := newFakeClient(
+		[]model.CoinBalance{{CoinName: "ETH", Total: d("2")}, {CoinName: "USDT_ETH", Total: d("100")}},
+		[]model.TxDetailItem{
+			{CoinName: "ETH", DepositAmount: d("5"), TxStatus: "SUCCESS", CreateTimeStamp: ms - 1000, TxTimeStamp: ms - 1000},
+			{CoinName: "ETH", DepositAmount: d("1"), TxFee: d("0.1"), TxStatus: "SUCCESS", CreateTimeStamp: ms + 1000, TxTimeStamp: ms + 1000},
+			{CoinName: "USDT_ETH", WithdrawAmount: d("30"), TxFee: d("0.02"), TxStatus: "SUCCESS", CreateTimeStamp: ms + 2000},
+			{CoinName: "ETH", WithdrawAmount: d("0.5"), TxFee: d("0.01"), TxStatus: "SUCCESS", CreateTimeStamp: ms + 3000, TxTimeStamp: ms + 3000},
+		},
+	)
+
+	snap, err := Reconstruct(context.Background(), client, ReconstructReq{WalletCode: "w1", At: at})
+	require.NoError(t, err)
+	assert.True(t, snap.Reconstructed)
+	require.Len(t, snap.Balances, 2)
+	assert.Equal(t, "ETH", snap.Balances[0].CoinName)
+	assert.Equal(t, "1.53", snap.Balances[0].Total.String()) // 2 - 1 + 0.5 + 0.01 + 0.02
+	assert.Equal(t, "130", snap.Balances[1].Total.String())
+}
+
+// TestReconstructSkipsUnsettled 测试失败、拒绝和处理中的提币不被撤销
+func TestReconstructSkipsUnsettled(t *testing.T) {
+	d := decimal.RequireFromString
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := model.NewTimestamp(at)
+	client := newFakeClient(
+		[]model.CoinBalance{{CoinName: "ETH", Total: d("2")}},
+		[]model.TxDetailItem{
+			{CoinName: "ETH", WithdrawAmount: d("1"), TxFee: d("0.01"), TxStatus: "FAILED", CreateTimeStamp: ms + 1000},
+			{CoinName: "ETH", WithdrawAmount: d("3"), TxStatus: "REJECTED", CreateTimeStamp: ms + 2000},
+			{CoinName: "ETH", WithdrawAmount: d("0.7"), TxStatus: "PENDING", CreateTimeStamp: ms + 3000},
+			{CoinName: "ETH", WithdrawAmount: d("0.5"), TxFee: d("0.01"), TxStatus: "success", CreateTimeStamp: ms + 4000},
+		},
+	)
+
+	snap, err := Reconstruct(context.Background(), client, ReconstructReq{WalletCode: "w1", At: at})
+	require.NoError(t, err)
+	require.Len(t, snap.Balances, 1)
+	assert.Equal(t, "2.51", snap.Balances[0].Total.String())
+}
+
+// TestReconstructLateConfirmation 测试创建早于At、上链晚于At的记录也被撤销
+func TestReconstructLateConfirmation(t *testing.T) {
+	d := decimal.RequireFromString
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := model.NewTimestamp(at)
+	client := newFakeClient(
+		[]model.CoinBalance{{CoinName: "ETH", Total: d("10")}},
+		[]model.TxDetailItem{
+			{CoinName: "ETH", DepositAmount: d("4"), TxStatus: "SUCCESS", CreateTimeStamp: ms - 60_000, TxTimeStamp: ms + 60_000},
+			{CoinName: "ETH", DepositAmount: d("1"), TxStatus: "SUCCESS", CreateTimeStamp: ms - 7_200_000, TxTimeStamp: ms + 60_000},
+		},
+	)
+
+	snap, err := Reconstruct(context.Background(), client, ReconstructReq{WalletCode: "w1", At: at})
+	require.NoError(t, err)
+	assert.Equal(t, "5", snap.Balances[0].Total.String())
+
+	// 余量不足两小时时，更早创建的记录查不到
+	snap, err = Reconstruct(context.Background(), client, ReconstructReq{WalletCode: "w1", At: at, Lookback: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, "6", snap.Balances[0].Total.String())
+}
@@ -0,0 +1,97 @@
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+)
+
+// Store 快照存储
+type Store interface {
+	Save(ctx context.Context, snap Snapshot) error
+}
+
+// MemoryStore 内存存储，适合测试
+type MemoryStore struct {
+	mu    sync.Mutex
+	snaps []Snapshot
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Save 保存快照
+func (m *MemoryStore) Save(_ context.Context, snap Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snaps = append(m.snaps, snap)
+	return nil
+}
+
+// Snapshots 返回已保存的快照
+func (m *MemoryStore) Snapshots() []Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Snapshot(nil), m.snaps...)
+}
+
+// FileStore 以JSON Lines追加写入文件
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore 创建文件存储
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save 追加一行快照
+func (f *FileStore) Save(_ context.Context, snap Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load 读取文件中的全部快照，文件不存在时返回空
+func (f *FileStore) Load() ([]Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var snap Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, scanner.Err()
+}
@@ -0,0 +1,220 @@
+package treasury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// GasFeedConfig gas补充配置
+type GasFeedConfig struct {
+	BID             string          // 业务线ID，为空时使用客户端环境的业务线
+	WalletCode      string          // 持有代币的充值钱包编号
+	TokenCoinName   string          // 代币币种（如 USDT_ETH、USDT_TRX）
+	MinTokenBalance decimal.Decimal // 代币可用余额不低于该值的地址才补gas
+	GasCoinName     string          // gas币种，为空时使用代币元数据中的FeeCoinName
+	MinGas          decimal.Decimal // gas余额低于该值时需要补充，为0时取TargetGas
+	TargetGas       decimal.Decimal // 补充后地址上的gas余额
+	GasWalletCode   string          // 出gas的钱包编号
+	MaxOrders       int             // 每次运行最多提交的订单数，0表示不限
+	OrderInterval   time.Duration   // 两个订单之间的最小间隔
+	OrderNoPrefix   string          // 订单号前缀，默认"gas-"
+	DryRun          bool            // 只生成报告，不提交订单
+}
+
+// GasTopUp 单个地址的补充结果
+type GasTopUp struct {
+	Address      string          `json:"address"`
+	TokenBalance decimal.Decimal `json:"token_balance"`
+	GasBalance   decimal.Decimal `json:"gas_balance"`
+	TopUp        decimal.Decimal `json:"top_up"`             // 需要补充的gas数量
+	OrderNo      string          `json:"order_no,omitempty"` // 提交的订单号
+	Skipped      string          `json:"skipped,omitempty"`  // 未提交的原因
+	Error        string          `json:"error,omitempty"`    // 提交失败原因
+}
+
+// GasFeedReport 一次gas补充的报告
+type GasFeedReport struct {
+	GasCoinName string          `json:"gas_coin_name"`
+	DryRun      bool            `json:"dry_run"`
+	TopUps      []GasTopUp      `json:"top_ups"`
+	Total       decimal.Decimal `json:"total"`   // 成功提交（或dry-run时计划提交）的gas总量
+	Orders      int             `json:"orders"`  // 成功提交（或计划提交）的订单数
+	Skipped     int             `json:"skipped"` // 因限流或余额不足未提交的地址数
+	Failed      int             `json:"failed"`  // 提交失败的地址数
+}
+
+// GasFeeder 为持有代币但gas不足的地址从gas钱包补充原生币
+type GasFeeder struct {
+	client cactus.Client
+	cfg    GasFeedConfig
+}
+
+// NewGasFeeder 创建gas补充器
+func NewGasFeeder(client cactus.Client, cfg GasFeedConfig) *GasFeeder {
+	if cfg.MinGas.IsZero() {
+		cfg.MinGas = cfg.TargetGas
+	}
+	if cfg.OrderNoPrefix == "" {
+		cfg.OrderNoPrefix = "gas-"
+	}
+	return &GasFeeder{client: client, cfg: cfg}
+}
+
+// Run 执行一次gas补充：找出gas不足的代币地址，计算补充量，按限流提交补充订单
+func (g *GasFeeder) Run(ctx context.Context) (*GasFeedReport, error) {
+	cfg := g.cfg
+	if cfg.WalletCode == "" || cfg.TokenCoinName == "" || cfg.GasWalletCode == "" {
+		return nil, errors.New("gas feed requires wallet code, token coin name and gas wallet code")
+	}
+	if !cfg.TargetGas.IsPositive() {
+		return nil, errors.New("gas feed requires a positive target gas")
+	}
+	token, err := g.client.GetCoinInfo(ctx, cfg.TokenCoinName)
+	if err != nil {
+		return nil, err
+	}
+	gasCoin := cfg.GasCoinName
+	if gasCoin == "" {
+		gasCoin = token.FeeCoinName
+	}
+	if gasCoin == "" || gasCoin == cfg.TokenCoinName {
+		return nil, fmt.Errorf("%s does not need a separate gas coin", cfg.TokenCoinName)
+	}
+
+	topUps, err := g.plan(ctx, token, gasCoin)
+	if err != nil {
+		return nil, err
+	}
+	available, err := g.fundingBalance(ctx, gasCoin)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GasFeedReport{GasCoinName: gasCoin, DryRun: cfg.DryRun, Total: decimal.Zero}
+	for _, t := range topUps {
+		switch {
+		case cfg.MaxOrders > 0 && report.Orders+report.Failed >= cfg.MaxOrders:
+			t.Skipped = fmt.Sprintf("rate limit: at most %d orders per run", cfg.MaxOrders)
+		case report.Total.Add(t.TopUp).GreaterThan(available):
+			t.Skipped = fmt.Sprintf("gas wallet %s has only %s %s available", cfg.GasWalletCode, available.Sub(report.Total), gasCoin)
+		}
+		if t.Skipped != "" {
+			report.Skipped++
+			report.TopUps = append(report.TopUps, t)
+			continue
+		}
+
+		t.OrderNo = newOrderNo(cfg.OrderNoPrefix)
+		if !cfg.DryRun {
+			if report.Orders+report.Failed > 0 {
+				if err := sleep(ctx, cfg.OrderInterval); err != nil {
+					return report, err
+				}
+			}
+			if err := g.submit(ctx, gasCoin, t); err != nil {
+				t.Error = err.Error()
+				report.Failed++
+				report.TopUps = append(report.TopUps, t)
+				continue
+			}
+		}
+		report.Orders++
+		report.Total = report.Total.Add(t.TopUp)
+		report.TopUps = append(report.TopUps, t)
+	}
+	return report, nil
+}
+
+// plan 找出代币余额达标但gas不足的地址并计算补充量
+func (g *GasFeeder) plan(ctx context.Context, token *model.CoinInfo, gasCoin string) ([]GasTopUp, error) {
+	cfg := g.cfg
+
+	// 先取出钱包内所有地址的gas余额，带上管理地址以覆盖ETH类钱包的全部地址
+	gasBalances := make(map[string]decimal.Decimal)
+	manage := true
+	err := scanAddresses(ctx, g.client, model.GetAddressesReq{
+		BID:                 cfg.BID,
+		WalletCode:          cfg.WalletCode,
+		CoinName:            gasCoin,
+		ManageWalletAddress: &manage,
+	}, func(info model.AddressInfo) error {
+		gasBalances[info.Address] = info.AvailableAmount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var topUps []GasTopUp
+	minBalance, err := minBalanceFilter(token, cfg.MinTokenBalance)
+	if err != nil {
+		return nil, err
+	}
+	hide := "true"
+	err = scanAddresses(ctx, g.client, model.GetAddressesReq{
+		BID:               cfg.BID,
+		WalletCode:        cfg.WalletCode,
+		CoinName:          cfg.TokenCoinName,
+		HideNoCoinAddress: &hide,
+		MinBalance:        minBalance,
+	}, func(info model.AddressInfo) error {
+		tokenBalance := info.AvailableAmount
+		if tokenBalance.IsZero() || tokenBalance.LessThan(cfg.MinTokenBalance) {
+			return nil
+		}
+		gas := gasBalances[info.Address]
+		if gas.GreaterThanOrEqual(cfg.MinGas) {
+			return nil
+		}
+		topUps = append(topUps, GasTopUp{
+			Address:      info.Address,
+			TokenBalance: tokenBalance,
+			GasBalance:   gas,
+			TopUp:        cfg.TargetGas.Sub(gas),
+		})
+		return nil
+	})
+	return topUps, err
+}
+
+// fundingBalance 查询gas钱包中gas币的可用余额
+func (g *GasFeeder) fundingBalance(ctx context.Context, gasCoin string) (decimal.Decimal, error) {
+	balance, err := g.client.GetWalletBalance(ctx, &model.GetWalletBalanceReq{
+		BID:        g.cfg.BID,
+		WalletCode: g.cfg.GasWalletCode,
+		CoinNames:  []string{gasCoin},
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, b := range balance.Balances {
+		if b.CoinName == gasCoin {
+			return b.Available, nil
+		}
+	}
+	return decimal.Zero, nil
+}
+
+// submit 从gas钱包向地址转入补充量
+func (g *GasFeeder) submit(ctx context.Context, gasCoin string, t GasTopUp) error {
+	remark := fmt.Sprintf("gas top-up for %s", g.cfg.TokenCoinName)
+	_, err := g.client.CreateOrder(ctx, &model.CreateOrderReq{
+		BID:            g.cfg.BID,
+		FromWalletCode: g.cfg.GasWalletCode,
+		CoinName:       gasCoin,
+		OrderNo:        t.OrderNo,
+		DestAddressItemList: []model.DestAddressItem{{
+			DestAddress: t.Address,
+			Amount:      t.TopUp,
+			Remark:      &remark,
+		}},
+	})
+	return err
+}
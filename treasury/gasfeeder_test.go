@@ -0,0 +1,97 @@
+package treasury
+
+import (
+	"context"
+	"testing"
+
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGasFeederRun 测试只给gas不足的代币地址补充，并受gas钱包余额限制
+func TestGasFeederRun(t *testing.T) {
+	client := newFakeClient()
+	client.Addresses["USDT_ETH"] = append(client.Addresses["USDT_ETH"], model.AddressInfo{
+		Address: "d", CoinName: "USDT_ETH", TotalAmount: decimal.NewFromInt(20), AvailableAmount: decimal.NewFromInt(20),
+	})
+	client.Wallets = map[string][]model.CoinBalance{
+		"gas": {{CoinName: "ETH", Available: decimal.RequireFromString("0.015")}},
+	}
+	cfg := GasFeedConfig{
+		WalletCode:      "eth-deposit",
+		TokenCoinName:   "USDT_ETH",
+		MinTokenBalance: decimal.NewFromInt(10),
+		MinGas:          decimal.RequireFromString("0.005"),
+		TargetGas:       decimal.RequireFromString("0.01"),
+		GasWalletCode:   "gas",
+	}
+
+	// dry-run 只生成报告
+	dryRun := cfg
+	dryRun.DryRun = true
+	dryRun.MaxOrders = 1
+	report, err := NewGasFeeder(client, dryRun).Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, client.Orders)
+	assert.Equal(t, 1, report.Orders)
+	assert.Equal(t, 1, report.Skipped)
+
+	report, err = NewGasFeeder(client, cfg).Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", report.GasCoinName)
+	require.Len(t, report.TopUps, 2)
+	assert.Equal(t, "b", report.TopUps[0].Address)
+	assert.Equal(t, "0.01", report.TopUps[0].TopUp.String())
+	assert.NotEmpty(t, report.TopUps[1].Skipped) // gas钱包余额只够一笔
+	assert.Equal(t, 1, report.Orders)
+
+	require.Len(t, client.Orders, 1)
+	order := client.Orders[0]
+	assert.Equal(t, "gas", order.FromWalletCode)
+	assert.Equal(t, "ETH", order.CoinName)
+	assert.Equal(t, "b", order.DestAddressItemList[0].DestAddress)
+}
+
+// TestGasFeederPrecision 测试补充量按地址余额精确计算，不经过浮点数
+func TestGasFeederPrecision(t *testing.T) {
+	client := newFakeClient()
+	client.Addresses["ETH"] = append(client.Addresses["ETH"], model.AddressInfo{
+		Address: "b", CoinName: "ETH", AvailableAmount: decimal.RequireFromString("0.000000000000000001"),
+	})
+	client.Wallets = map[string][]model.CoinBalance{
+		"gas": {{CoinName: "ETH", Available: decimal.NewFromInt(1)}},
+	}
+
+	report, err := NewGasFeeder(client, GasFeedConfig{
+		WalletCode:      "eth-deposit",
+		TokenCoinName:   "USDT_ETH",
+		MinTokenBalance: decimal.NewFromInt(10),
+		TargetGas:       decimal.RequireFromString("0.01"),
+		GasWalletCode:   "gas",
+	}).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, report.TopUps, 1)
+	assert.Equal(t, "0.000000000000000001", report.TopUps[0].GasBalance.String())
+	assert.Equal(t, "0.009999999999999999", report.TopUps[0].TopUp.String())
+	require.Len(t, client.Orders, 1)
+	assert.Equal(t, "0.009999999999999999", client.Orders[0].DestAddressItemList[0].Amount.String())
+}
+
+// TestGasFeederMinTokenBalanceRange 测试代币余额下限换算成最小单位超出int64时被拒绝
+func TestGasFeederMinTokenBalanceRange(t *testing.T) {
+	client := newFakeClient()
+	client.Coins["DAI_ETH"] = model.CoinInfo{CoinName: "DAI_ETH", ContractAddress: "0x6b1754", Decimals: 18, FeeCoinName: "ETH"}
+	_, err := NewGasFeeder(client, GasFeedConfig{
+		WalletCode:      "eth-deposit",
+		TokenCoinName:   "DAI_ETH",
+		MinTokenBalance: decimal.NewFromInt(10),
+		MinGas:          decimal.RequireFromString("0.005"),
+		TargetGas:       decimal.RequireFromString("0.01"),
+		GasWalletCode:   "gas",
+	}).Run(context.Background())
+	assert.ErrorContains(t, err, "beyond the min_balance range")
+	assert.Empty(t, client.Orders)
+}
@@ -0,0 +1,93 @@
+// Package treasury 提供基于Cactus客户端的资金运营编排：代币归集、gas补充等
+package treasury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// scanPageSize 扫描地址时每页的数量
+const scanPageSize = 100
+
+// scanAddresses 自动翻页遍历钱包地址，req中的Offset和Limit会被覆盖
+func scanAddresses(ctx context.Context, client cactus.Client, req model.GetAddressesReq, fn func(model.AddressInfo) error) error {
+	offset, limit := 0, scanPageSize
+	for {
+		req.Offset, req.Limit = &offset, &limit
+		resp, err := client.GetAddressList(ctx, &req)
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Data.List {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		offset += len(resp.Data.List)
+		if len(resp.Data.List) == 0 || offset >= resp.Data.Total {
+			return nil
+		}
+	}
+}
+
+// minBalanceFilter 把币数量换算成地址列表的min_balance（最小单位），超出int64时报错而不是回绕
+func minBalanceFilter(coin *model.CoinInfo, amount decimal.Decimal) (*int64, error) {
+	units := coin.ToMinUnit(amount)
+	if units.GreaterThan(decimal.NewFromInt(math.MaxInt64)) {
+		return nil, fmt.Errorf("%s %s is %s in minimum units, beyond the min_balance range", amount, coin.CoinName, units)
+	}
+	minBalance := units.IntPart()
+	return &minBalance, nil
+}
+
+// isSegregated 是否为分离地址钱包
+func isSegregated(walletType string) bool {
+	return strings.HasPrefix(walletType, "SEGREGATED")
+}
+
+// newOrderNo 生成带前缀的唯一订单号
+func newOrderNo(prefix string) string {
+	return prefix + strings.ReplaceAll(uuid.New().String(), "-", "")
+}
+
+// gasBalance 查询地址上gas币的可用余额，地址上没有该币种时视为0
+func gasBalance(ctx context.Context, client cactus.Client, bid, walletCode, address, gasCoin string) (decimal.Decimal, error) {
+	balance, err := client.GetAddressBalance(ctx, &model.GetAddressBalanceReq{
+		BID:        bid,
+		WalletCode: walletCode,
+		Address:    address,
+		CoinName:   gasCoin,
+	})
+	if errors.Is(err, cactus.ErrAddressNotFound) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return balance.Available, nil
+}
+
+// sleep 等待d或ctx结束
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package treasury
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-cactus/cactus"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// SweepConfig 归集配置
+type SweepConfig struct {
+	BID           string          // 业务线ID，为空时使用客户端环境的业务线
+	WalletCode    string          // 分离地址钱包编号
+	CoinName      string          // 归集币种（如 USDT_ETH）
+	DestAddress   string          // 归集目标地址
+	Threshold     decimal.Decimal // 地址可用余额不低于该值才归集
+	GasCoinName   string          // gas币种，为空时使用币种元数据中的FeeCoinName
+	MinGas        decimal.Decimal // 代币归集时地址上至少需要的gas币数量，归集代币时必须大于0
+	BatchSize     int             // 每批提交的订单数，默认10
+	BatchInterval time.Duration   // 批次之间的间隔
+	OrderNoPrefix string          // 订单号前缀，默认"sweep-"
+	DryRun        bool            // 只生成报告，不提交订单
+}
+
+// SweepResult 单个地址的归集结果
+type SweepResult struct {
+	Address string          `json:"address"`
+	Amount  decimal.Decimal `json:"amount"`             // 归集数量（提交时的可用余额）
+	OrderNo string          `json:"order_no,omitempty"` // 提交的订单号
+	Skipped string          `json:"skipped,omitempty"`  // 跳过原因
+	Error   string          `json:"error,omitempty"`    // 提交失败原因
+}
+
+// SweepReport 一次归集的报告
+type SweepReport struct {
+	CoinName string          `json:"coin_name"`
+	DryRun   bool            `json:"dry_run"`
+	Results  []SweepResult   `json:"results"`
+	Swept    decimal.Decimal `json:"swept"`   // 成功提交（或dry-run时计划提交）的归集总量
+	Orders   int             `json:"orders"`  // 成功提交（或计划提交）的订单数
+	Skipped  int             `json:"skipped"` // 跳过的地址数
+	Failed   int             `json:"failed"`  // 提交失败的地址数
+}
+
+// Sweeper 扫描分离地址钱包，把余额超过阈值的代币归集到目标地址
+type Sweeper struct {
+	client cactus.Client
+	cfg    SweepConfig
+}
+
+// NewSweeper 创建归集器
+func NewSweeper(client cactus.Client, cfg SweepConfig) *Sweeper {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.OrderNoPrefix == "" {
+		cfg.OrderNoPrefix = "sweep-"
+	}
+	return &Sweeper{client: client, cfg: cfg}
+}
+
+// Run 执行一次归集：扫描候选地址、检查gas、分批提交归集订单
+func (s *Sweeper) Run(ctx context.Context) (*SweepReport, error) {
+	cfg := s.cfg
+	if cfg.WalletCode == "" || cfg.CoinName == "" || cfg.DestAddress == "" {
+		return nil, errors.New("sweep requires wallet code, coin name and destination address")
+	}
+	coin, err := s.client.GetCoinInfo(ctx, cfg.CoinName)
+	if err != nil {
+		return nil, err
+	}
+	gasCoin := cfg.GasCoinName
+	if gasCoin == "" {
+		gasCoin = coin.FeeCoinName
+	}
+	needGas := coin.IsToken() && gasCoin != "" && gasCoin != cfg.CoinName
+	if needGas && !cfg.MinGas.IsPositive() {
+		return nil, fmt.Errorf("sweeping %s requires a positive MinGas of %s", cfg.CoinName, gasCoin)
+	}
+
+	candidates, err := s.candidates(ctx, coin)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SweepReport{CoinName: cfg.CoinName, DryRun: cfg.DryRun, Swept: decimal.Zero}
+	pending := 0
+	for _, info := range candidates {
+		result := SweepResult{Address: info.Address, Amount: info.AvailableAmount}
+
+		if needGas {
+			gas, err := gasBalance(ctx, s.client, cfg.BID, cfg.WalletCode, info.Address, gasCoin)
+			if err != nil {
+				return nil, err
+			}
+			if gas.LessThan(cfg.MinGas) {
+				result.Skipped = fmt.Sprintf("insufficient gas: %s %s < %s", gas, gasCoin, cfg.MinGas)
+				report.Skipped++
+				report.Results = append(report.Results, result)
+				continue
+			}
+		}
+
+		if pending == cfg.BatchSize {
+			if err := sleep(ctx, cfg.BatchInterval); err != nil {
+				return report, err
+			}
+			pending = 0
+		}
+		result.OrderNo = newOrderNo(cfg.OrderNoPrefix)
+		if !cfg.DryRun {
+			pending++
+			if err := s.submit(ctx, result); err != nil {
+				result.Error = err.Error()
+				report.Failed++
+				report.Results = append(report.Results, result)
+				continue
+			}
+		}
+		report.Orders++
+		report.Swept = report.Swept.Add(result.Amount)
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// candidates 扫描余额不低于阈值的分离地址，利用MinBalance让服务端先过滤
+func (s *Sweeper) candidates(ctx context.Context, coin *model.CoinInfo) ([]model.AddressInfo, error) {
+	cfg := s.cfg
+	minBalance, err := minBalanceFilter(coin, cfg.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	hide, sortDesc := "true", "DESC"
+
+	var out []model.AddressInfo
+	err = scanAddresses(ctx, s.client, model.GetAddressesReq{
+		BID:               cfg.BID,
+		WalletCode:        cfg.WalletCode,
+		CoinName:          cfg.CoinName,
+		HideNoCoinAddress: &hide,
+		SortByBalance:     &sortDesc,
+		MinBalance:        minBalance,
+	}, func(info model.AddressInfo) error {
+		if !isSegregated(info.WalletType) {
+			return fmt.Errorf("wallet %s is %s, sweeping needs a segregated-address wallet", cfg.WalletCode, info.WalletType)
+		}
+		if info.Address == cfg.DestAddress {
+			return nil
+		}
+		if info.AvailableAmount.GreaterThanOrEqual(cfg.Threshold) {
+			out = append(out, info)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// submit 提交单个地址的归集订单：从该地址全额转出到目标地址，数量为扫描时的可用余额
+func (s *Sweeper) submit(ctx context.Context, result SweepResult) error {
+	cfg := s.cfg
+	from := result.Address
+	aggre := true
+	_, err := s.client.CreateOrder(ctx, &model.CreateOrderReq{
+		BID:            cfg.BID,
+		FromAddress:    &from,
+		FromWalletCode: cfg.WalletCode,
+		CoinName:       cfg.CoinName,
+		OrderNo:        result.OrderNo,
+		DestAddressItemList: []model.DestAddressItem{{
+			DestAddress:     cfg.DestAddress,
+			Amount:          result.Amount,
+			IsAllWithdrawal: true,
+			ContractAggre:   &aggre,
+		}},
+	})
+	return err
+}
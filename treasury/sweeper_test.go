@@ -0,0 +1,79 @@
+package treasury
+
+import (
+	"context"
+	"testing"
+
+	"go-cactus/internal/cactustest"
+	"go-cactus/model"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeClient 构造一个ETH分离地址钱包：a有代币和gas，b只有代币，c余额低于阈值
+func newFakeClient() *cactustest.Client {
+	addr := func(address, coin, amount string) model.AddressInfo {
+		return model.AddressInfo{
+			WalletType: "SEGREGATED_ADDRESS", Address: address, CoinName: coin,
+			TotalAmount: decimal.RequireFromString(amount), AvailableAmount: decimal.RequireFromString(amount),
+		}
+	}
+	return &cactustest.Client{
+		Coins: map[string]model.CoinInfo{
+			"USDT_ETH": {CoinName: "USDT_ETH", ContractAddress: "0xdac17f", Decimals: 6, FeeCoinName: "ETH"},
+			"ETH":      {CoinName: "ETH", Decimals: 18, FeeCoinName: "ETH"},
+		},
+		Addresses: map[string][]model.AddressInfo{
+			"USDT_ETH": {addr("a", "USDT_ETH", "100.000001"), addr("b", "USDT_ETH", "50"), addr("c", "USDT_ETH", "5")},
+			"ETH":      {addr("a", "ETH", "0.01")},
+		},
+	}
+}
+
+// TestSweeperRun 测试归集只提交余额达标且gas充足的地址
+func TestSweeperRun(t *testing.T) {
+	client := newFakeClient()
+	sweeper := NewSweeper(client, SweepConfig{
+		WalletCode:  "eth-deposit",
+		CoinName:    "USDT_ETH",
+		DestAddress: "collector",
+		Threshold:   decimal.NewFromInt(10),
+		MinGas:      decimal.RequireFromString("0.005"),
+	})
+
+	report, err := sweeper.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Orders)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, "100.000001", report.Swept.String())
+	require.Len(t, client.Orders, 1)
+	order := client.Orders[0]
+	assert.Equal(t, "a", *order.FromAddress)
+	assert.Equal(t, "collector", order.DestAddressItemList[0].DestAddress)
+	assert.Equal(t, "100.000001", order.DestAddressItemList[0].Amount.String())
+	assert.True(t, order.DestAddressItemList[0].IsAllWithdrawal)
+	assert.True(t, *order.DestAddressItemList[0].ContractAggre)
+}
+
+// TestSweeperConfigErrors 测试代币归集没有设置MinGas，以及阈值换算成最小单位超出int64时被拒绝
+func TestSweeperConfigErrors(t *testing.T) {
+	client := newFakeClient()
+	client.Coins["DAI_ETH"] = model.CoinInfo{CoinName: "DAI_ETH", ContractAddress: "0x6b1754", Decimals: 18, FeeCoinName: "ETH"}
+	cfg := SweepConfig{
+		WalletCode:  "eth-deposit",
+		CoinName:    "USDT_ETH",
+		DestAddress: "collector",
+		Threshold:   decimal.NewFromInt(10),
+	}
+
+	_, err := NewSweeper(client, cfg).Run(context.Background())
+	assert.EqualError(t, err, "sweeping USDT_ETH requires a positive MinGas of ETH")
+
+	cfg.CoinName, cfg.MinGas = "DAI_ETH", decimal.RequireFromString("0.005")
+	_, err = NewSweeper(client, cfg).Run(context.Background())
+	assert.ErrorContains(t, err, "beyond the min_balance range")
+	assert.Empty(t, client.Orders)
+}